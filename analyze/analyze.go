@@ -0,0 +1,177 @@
+// Package analyze is the supported Go API of go-size-analyzer.
+//
+// It analyzes a compiled Go binary and reports how much of its size each
+// package, file, function, symbol and section takes:
+//
+//	r, err := analyze.File(ctx, "./app", analyze.SkipDisasm())
+//	if err != nil {
+//		return err
+//	}
+//	for _, p := range r.Packages() {
+//		fmt.Println(p.Name, p.Size)
+//	}
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Zxilly/go-size-analyzer/internal"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// Phase is a step of the analysis pipeline.
+type Phase = progress.Phase
+
+const (
+	PhaseParse       = progress.PhaseParse
+	PhaseSections    = progress.PhaseSections
+	PhasePackages    = progress.PhasePackages
+	PhaseDwarf       = progress.PhaseDwarf
+	PhaseSymbol      = progress.PhaseSymbol
+	PhaseTypes       = progress.PhaseTypes
+	PhasePclntabMeta = progress.PhasePclntabMeta
+	PhaseDisasm      = progress.PhaseDisasm
+	PhaseImports     = progress.PhaseImports
+	PhaseCoverage    = progress.PhaseCoverage
+)
+
+// Progress is an event of the analysis, see WithProgress.
+type Progress = progress.Event
+
+type config struct {
+	internal.Options
+}
+
+// Option configures an analysis, without options every pass runs.
+type Option func(*config)
+
+// SkipSymbol skips the symbol table pass.
+func SkipSymbol() Option {
+	return func(c *config) {
+		c.SkipSymbol = true
+	}
+}
+
+// SkipDisasm skips the disassembly pass, which is the slowest one.
+func SkipDisasm() Option {
+	return func(c *config) {
+		c.SkipDisasm = true
+	}
+}
+
+// SkipDwarf skips the DWARF pass.
+func SkipDwarf() Option {
+	return func(c *config) {
+		c.SkipDwarf = true
+	}
+}
+
+// WithImports fills Package.ImportedBy from the package sources, when they can be found.
+func WithImports() Option {
+	return func(c *config) {
+		c.Imports = true
+	}
+}
+
+// WithCalls fills Function.Calls and Package.UsedBy from the direct calls found
+// by the disassembly, which needs no source. It has no effect with SkipDisasm.
+func WithCalls() Option {
+	return func(c *config) {
+		c.Calls = true
+	}
+}
+
+// WithFoldWrappers folds the closures, method values, go and defer wrappers and
+// autogenerated method wrappers into Function.Wrappers of the function they belong to.
+func WithFoldWrappers() Option {
+	return func(c *config) {
+		c.FoldWrappers = true
+	}
+}
+
+// WithModules aggregates the packages by their owning module in Result.Modules.
+func WithModules() Option {
+	return func(c *config) {
+		c.Modules = true
+	}
+}
+
+// WithGenerics groups the instantiations by their generic function in Result.Generics.
+func WithGenerics() Option {
+	return func(c *config) {
+		c.Generics = true
+	}
+}
+
+// WithInlines reports the code inlined from other packages in Result.Inlines,
+// read from the DWARF. It has no effect with SkipDwarf.
+func WithInlines() Option {
+	return func(c *config) {
+		c.Inlines = true
+	}
+}
+
+// WithDebugFile loads the DWARF from the debug file at path, e.g. a .debug file
+// or a .dSYM bundle, it must match the binary.
+func WithDebugFile(path string) Option {
+	return func(c *config) {
+		c.DebugFile = path
+	}
+}
+
+// WithDebugDirs searches dirs for the debug file of a binary without DWARF,
+// by the build id or the debug link, before the default directories.
+func WithDebugDirs(dirs ...string) Option {
+	return func(c *config) {
+		c.DebugDirs = append(c.DebugDirs, dirs...)
+	}
+}
+
+// WithArch picks the slice of a universal Mach-O binary by the Go architecture,
+// e.g. arm64. It is required if the binary holds more than one, see FileSlices.
+func WithArch(arch string) Option {
+	return func(c *config) {
+		c.Arch = arch
+	}
+}
+
+// WithProgress calls fn as the analysis advances. fn may be called from
+// worker goroutines, but never concurrently.
+func WithProgress(fn func(Progress)) Option {
+	return func(c *config) {
+		c.Progress = fn
+	}
+}
+
+// Reader analyzes the binary of the given size read from r, name is only used as Result.Name.
+// The analysis stops and returns the error of ctx once ctx is done.
+func Reader(ctx context.Context, name string, r io.ReaderAt, size uint64, opts ...Option) (*Result, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	raw, err := internal.AnalyzeContext(ctx, name, r, size, c.Options)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{raw: raw}, nil
+}
+
+// File analyzes the binary at path.
+func File(ctx context.Context, path string, opts ...Option) (*Result, error) {
+	f, err := utils.OpenBinary(path)
+	if err != nil {
+		return nil, fmt.Errorf("open binary %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r, err := Reader(ctx, path, f, uint64(f.Len()), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("analyze %s: %w", path, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,211 @@
+//go:build !js && !wasm
+
+package analyze
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
+)
+
+func TestOptions(t *testing.T) {
+	var events []Progress
+
+	var c config
+	for _, opt := range []Option{SkipSymbol(), SkipDisasm(), SkipDwarf(), WithImports(), WithCalls(), WithModules(), WithGenerics(), WithDebugFile("app.debug"), WithDebugDirs("a", "b"), WithArch("arm64"), WithProgress(func(p Progress) {
+		events = append(events, p)
+	})} {
+		opt(&c)
+	}
+
+	assert.True(t, c.SkipSymbol)
+	assert.True(t, c.SkipDisasm)
+	assert.True(t, c.SkipDwarf)
+	assert.True(t, c.Imports)
+	assert.True(t, c.Calls)
+	assert.True(t, c.Modules)
+	assert.True(t, c.Generics)
+	assert.Equal(t, "app.debug", c.DebugFile)
+	assert.Equal(t, []string{"a", "b"}, c.DebugDirs)
+	assert.Equal(t, "arm64", c.Arch)
+
+	c.Progress.Report(Progress{Phase: PhaseParse})
+	assert.Equal(t, []Progress{{Phase: PhaseParse}}, events)
+}
+
+func TestReaderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	_, err := Reader(ctx, "bin", bytes.NewReader(nil), 0, WithProgress(func(Progress) {
+		called = true
+	}))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called)
+}
+
+func TestFile(t *testing.T) {
+	bin, err := os.Executable()
+	require.NoError(t, err)
+
+	var phases []Phase
+	r, err := File(context.Background(), bin, SkipDisasm(), SkipDwarf(), WithProgress(func(p Progress) {
+		phases = append(phases, p.Phase)
+	}))
+	require.NoError(t, err)
+
+	require.NotEmpty(t, phases)
+	assert.Equal(t, PhaseParse, phases[0])
+	assert.Equal(t, PhaseCoverage, phases[len(phases)-1])
+	assert.NotContains(t, phases, PhaseDisasm)
+
+	assert.NotZero(t, r.Size())
+	assert.Contains(t, r.Analyzers(), entity.AnalyzerPclntab)
+
+	var found bool
+	for _, p := range r.Packages() {
+		if p.Name == "testing" {
+			found = true
+			assert.Equal(t, PackageTypeStd, p.Type)
+			assert.NotEmpty(t, p.Files)
+		}
+	}
+	assert.True(t, found)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteJSON(&buf, true))
+	assert.Contains(t, buf.String(), `"testing"`)
+}
+
+func TestResultAccessors(t *testing.T) {
+	sub := entity.NewPackage()
+	sub.Name = "example.com/a/b"
+	sub.Type = entity.PackageTypeVendor
+	sub.Size = 300
+	sub.Files = []*entity.File{{FilePath: "b.go", Functions: []*entity.Function{
+		{Name: "M", Receiver: "*T", Addr: 0x1000, CodeSize: 100, Type: entity.FuncTypeMethod,
+			PclnSize: entity.PclnSymbolSize{Header: 20, PCData: map[string]int{"0": 5}}, Calls: []uint64{0x3000},
+			Wrappers: []*entity.Function{{Name: "M-fm", Receiver: "*T", Addr: 0x1080, CodeSize: 10, Type: entity.FuncTypeMethod}}},
+	}}}
+	sub.UsedBy = []string{"main"}
+	sub.Symbols = []*entity.Symbol{{Name: "example.com/a/b.table", Addr: 0x2000, Size: 64, Type: entity.AddrTypeData}}
+
+	top := entity.NewPackage()
+	top.Name = "example.com/a"
+	top.Type = entity.PackageTypeVendor
+	top.Size = 400
+	top.Retained = 400
+	top.SubPackages["b"] = sub
+
+	r := &Result{raw: &result.Result{
+		Name:      "bin",
+		Size:      1000,
+		Analyzers: []entity.Analyzer{entity.AnalyzerPclntab},
+		Packages:  entity.PackageMap{"example.com/a": top},
+		Modules: []*entity.Module{
+			{Path: "example.com/a", Version: "v1.0.0", Type: entity.PackageTypeVendor,
+				Packages: []string{"example.com/a/b"}, Size: 400},
+		},
+		Generics: []*entity.Generic{{Package: "example.com/a/b", Name: "Map", Size: 30, Instances: []*entity.Instance{
+			{TypeArgs: "go.shape.int", Shape: true, Addr: 0x1100, Size: 30},
+		}}},
+		Inlines: []*entity.Inline{{Package: "main", Function: "example.com/a/b.Get", Origin: "example.com/a/b", Calls: 3, Size: 24}},
+		Imports: []why.Edge{{From: "example.com/a", To: "example.com/a/b"}, {From: "main", To: "example.com/a", Uses: true}},
+		Sections: []*entity.Section{
+			{Name: ".text", FileSize: 500, KnownSize: 400},
+			{Name: ".data", FileSize: 100, KnownSize: 64},
+		},
+	}}
+
+	assert.Equal(t, "bin", r.Name())
+	assert.Equal(t, uint64(1000), r.Size())
+	assert.Equal(t, []string{entity.AnalyzerPclntab}, r.Analyzers())
+
+	assert.Equal(t, []Package{{
+		Name:       "example.com/a",
+		Type:       PackageTypeVendor,
+		Size:       400,
+		Files:      []SourceFile{},
+		Symbols:    []Symbol{},
+		ImportedBy: []string{},
+		Retained:   400,
+		SubPackages: []Package{{
+			Name:        "example.com/a/b",
+			Type:        PackageTypeVendor,
+			Size:        300,
+			SubPackages: []Package{},
+			Files: []SourceFile{{Path: "b.go", Functions: []Function{
+				{Name: "M", Receiver: "*T", Addr: 0x1000, CodeSize: 100, PclnSize: 25, Calls: []uint64{0x3000},
+					Wrappers: []Function{{Name: "M-fm", Receiver: "*T", Addr: 0x1080, CodeSize: 10}}},
+			}}},
+			Symbols:    []Symbol{{Name: "example.com/a/b.table", Type: SymbolTypeData, Addr: 0x2000, Size: 64}},
+			ImportedBy: []string{},
+			UsedBy:     []string{"main"},
+		}},
+	}}, r.Packages())
+
+	assert.Equal(t, uint64(135), r.Packages()[0].SubPackages[0].Files[0].Functions[0].FullSize())
+
+	assert.Equal(t, []Module{{
+		Path:     "example.com/a",
+		Version:  "v1.0.0",
+		Type:     PackageTypeVendor,
+		Packages: []string{"example.com/a/b"},
+		Size:     400,
+	}}, r.Modules())
+
+	assert.Equal(t, []Generic{{Package: "example.com/a/b", Name: "Map", Size: 30, Instances: []Instance{
+		{TypeArgs: "go.shape.int", Shape: true, Addr: 0x1100, Size: 30},
+	}}}, r.Generics())
+
+	assert.Equal(t, []Inline{{Package: "main", Function: "example.com/a/b.Get", Origin: "example.com/a/b", Calls: 3, Size: 24}}, r.Inlines())
+
+	assert.Equal(t, []Import{{From: "example.com/a", To: "example.com/a/b"}, {From: "main", To: "example.com/a", Uses: true}}, r.Imports())
+
+	sections := r.Sections()
+	require.Len(t, sections, 2)
+	assert.Equal(t, ".data", sections[0].Name)
+	assert.Equal(t, ".text", sections[1].Name)
+
+	// accessors return copies
+	r.Packages()[0].SubPackages[0].Name = "changed"
+	assert.Equal(t, "example.com/a/b", r.Packages()[0].SubPackages[0].Name)
+}
+
+func TestDiff(t *testing.T) {
+	newRaw := func(name string, size, pkgSize uint64) *Result {
+		p := entity.NewPackage()
+		p.Name = "main"
+		p.Type = entity.PackageTypeMain
+		p.Size = pkgSize
+		return &Result{raw: &result.Result{
+			Name:     name,
+			Size:     size,
+			Packages: entity.PackageMap{"main": p},
+			Sections: []*entity.Section{{Name: ".text", FileSize: 100, KnownSize: 50}},
+		}}
+	}
+
+	d := Diff(newRaw("old", 1000, 100), newRaw("new", 1200, 150))
+
+	assert.Equal(t, "old", d.OldName)
+	assert.Equal(t, "new", d.NewName)
+	assert.Equal(t, int64(1000), d.OldSize)
+	assert.Equal(t, int64(1200), d.NewSize)
+	assert.Equal(t, []PackageDiff{{
+		DiffEntry: DiffEntry{Name: "main", Old: 100, New: 150, Change: ChangeModify},
+		Type:      PackageTypeMain,
+	}}, d.Packages)
+	assert.Equal(t, int64(50), d.Packages[0].Delta())
+	assert.Empty(t, d.Sections)
+	assert.Empty(t, d.Functions)
+}
@@ -0,0 +1,132 @@
+//go:build !js && !wasm
+
+package analyze
+
+import (
+	"github.com/Zxilly/go-size-analyzer/internal/diff"
+)
+
+// Change is how an entry differs between the old and the new result.
+type Change string
+
+const (
+	ChangeAdd    Change = "add"
+	ChangeRemove Change = "remove"
+	ChangeModify Change = "change"
+)
+
+// DiffEntry is a sized entry present in at least one of the results,
+// Old or New is zero when it is missing on that side.
+type DiffEntry struct {
+	Name   string
+	Old    int64
+	New    int64
+	Change Change
+}
+
+// Delta is New minus Old.
+func (e DiffEntry) Delta() int64 {
+	return e.New - e.Old
+}
+
+type PackageDiff struct {
+	DiffEntry
+	Type string
+}
+
+// SectionDiff compares the unknown part of a section, that is FileSize minus KnownSize.
+type SectionDiff struct {
+	DiffEntry
+	OldFileSize  int64
+	NewFileSize  int64
+	OldKnownSize int64
+	NewKnownSize int64
+}
+
+// FunctionDiff compares code plus pclntab size, Name is qualified by Package.
+type FunctionDiff struct {
+	DiffEntry
+	Package     string
+	OldCodeSize int64
+	NewCodeSize int64
+	OldPclnSize int64
+	NewPclnSize int64
+}
+
+// SymbolDiff compares data symbols, Group is one of type, itab, pclntab, embed and other.
+type SymbolDiff struct {
+	DiffEntry
+	Package string
+	Group   string
+}
+
+// DiffResult holds the changed entries only, each list is sorted by Delta descending.
+type DiffResult struct {
+	OldName string
+	NewName string
+	OldSize int64
+	NewSize int64
+
+	Packages []PackageDiff
+	Sections []SectionDiff
+	// Functions is empty if a result has no function detail.
+	Functions []FunctionDiff
+	Symbols   []SymbolDiff
+}
+
+func newDiffEntry(b diff.Base) DiffEntry {
+	return DiffEntry{
+		Name:   b.Name,
+		Old:    b.From,
+		New:    b.To,
+		Change: Change(b.ChangeType),
+	}
+}
+
+// Diff compares two results the same way as the diff mode of gsa.
+func Diff(oldResult, newResult *Result) *DiffResult {
+	d := diff.Compare(oldResult.raw, newResult.raw)
+
+	ret := &DiffResult{
+		OldName:   d.OldName,
+		NewName:   d.NewName,
+		OldSize:   d.OldSize,
+		NewSize:   d.NewSize,
+		Packages:  make([]PackageDiff, len(d.Packages)),
+		Sections:  make([]SectionDiff, len(d.Sections)),
+		Functions: make([]FunctionDiff, len(d.Functions)),
+		Symbols:   make([]SymbolDiff, len(d.Symbols)),
+	}
+
+	for i, p := range d.Packages {
+		ret.Packages[i] = PackageDiff{DiffEntry: newDiffEntry(p.Base), Type: p.Type}
+	}
+	for i, s := range d.Sections {
+		ret.Sections[i] = SectionDiff{
+			DiffEntry:    newDiffEntry(s.Base),
+			OldFileSize:  s.OldFileSize,
+			NewFileSize:  s.NewFileSize,
+			OldKnownSize: s.OldKnownSize,
+			NewKnownSize: s.NewKnownSize,
+		}
+	}
+	for i, f := range d.Functions {
+		ret.Functions[i] = FunctionDiff{
+			DiffEntry:   newDiffEntry(f.Base),
+			Package:     f.Package,
+			OldCodeSize: f.OldCodeSize,
+			NewCodeSize: f.NewCodeSize,
+			OldPclnSize: f.OldPclnSize,
+			NewPclnSize: f.NewPclnSize,
+		}
+	}
+	for i, s := range d.Symbols {
+		ret.Symbols[i] = SymbolDiff{
+			DiffEntry: newDiffEntry(s.Base),
+			Package:   s.Package,
+			Group:     string(s.Group),
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,56 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Zxilly/go-size-analyzer/internal"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// ErrNotFat is returned by ReaderSlices and FileSlices for the binaries that
+// are not universal Mach-O binaries.
+var ErrNotFat = internal.ErrNotFat
+
+// Slice is the analyzed Mach-O file of an architecture in a universal binary.
+type Slice struct {
+	Arch   string
+	Offset uint64
+	Size   uint64
+	Result *Result
+}
+
+// ReaderSlices analyzes every slice of the universal binary of the given size
+// read from r, in the order of its header. WithArch is ignored.
+func ReaderSlices(ctx context.Context, name string, r io.ReaderAt, size uint64, opts ...Option) ([]Slice, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	raw, err := internal.AnalyzeFat(ctx, name, r, size, c.Options)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Slice, len(raw.Slices))
+	for i, s := range raw.Slices {
+		ret[i] = Slice{Arch: s.Arch, Offset: s.Offset, Size: s.Size, Result: &Result{raw: s.Result}}
+	}
+	return ret, nil
+}
+
+// FileSlices analyzes every slice of the universal binary at path.
+func FileSlices(ctx context.Context, path string, opts ...Option) ([]Slice, error) {
+	f, err := utils.OpenBinary(path)
+	if err != nil {
+		return nil, fmt.Errorf("open binary %s: %w", path, err)
+	}
+	defer f.Close()
+
+	ret, err := ReaderSlices(ctx, path, f, uint64(f.Len()), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("analyze %s: %w", path, err)
+	}
+	return ret, nil
+}
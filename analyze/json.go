@@ -0,0 +1,17 @@
+//go:build !js && !wasm
+
+package analyze
+
+import (
+	"io"
+
+	"github.com/Zxilly/go-size-analyzer/internal/printer"
+)
+
+// WriteJSON writes the result in the json format of gsa, which the diff mode of gsa accepts.
+// compact replaces the files of packages with their size.
+func (r *Result) WriteJSON(w io.Writer, compact bool) error {
+	return printer.JSON(r.raw, w, &printer.JSONOption{
+		HideDetail: compact,
+	})
+}
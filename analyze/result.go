@@ -0,0 +1,316 @@
+package analyze
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// Package types, see Package.Type.
+const (
+	PackageTypeMain      = entity.PackageTypeMain
+	PackageTypeStd       = entity.PackageTypeStd
+	PackageTypeVendor    = entity.PackageTypeVendor
+	PackageTypeGenerated = entity.PackageTypeGenerated
+	PackageTypeUnknown   = entity.PackageTypeUnknown
+	PackageTypeCGO       = entity.PackageTypeCGO
+)
+
+// Symbol types, see Symbol.Type.
+const (
+	SymbolTypeText = entity.AddrTypeText
+	SymbolTypeData = entity.AddrTypeData
+)
+
+// Result is an analyzed binary. It is read only, the accessors return copies.
+type Result struct {
+	raw *result.Result
+}
+
+type Package struct {
+	Name string
+	Type string
+	Size uint64
+
+	// SubPackages are sorted by name.
+	SubPackages []Package
+	Files       []SourceFile
+	// Symbols may overlap, they should not be summed as the package size.
+	Symbols []Symbol
+
+	// ImportedBy is only filled with WithImports.
+	ImportedBy []string
+	// UsedBy are the packages calling the package directly, only filled with WithCalls.
+	UsedBy []string
+	// Retained is the size of the package and of the packages only imported
+	// through it, what dropping it would save. It is only filled with
+	// WithImports, or with WithCalls from the uses if no import is found,
+	// and zero if the package is not imported from main.
+	Retained uint64
+}
+
+// SourceFile is a source file of a package, with the functions compiled from it.
+type SourceFile struct {
+	Path      string
+	Functions []Function
+}
+
+type Function struct {
+	Name string
+	// Receiver is the receiver type of a method, empty for functions.
+	Receiver string
+	Addr     uint64
+	CodeSize uint64
+	// PclnSize is the size of the function metadata in pclntab.
+	PclnSize uint64
+	// Calls are the addresses of the functions called directly, only filled with WithCalls.
+	Calls []uint64
+	// Wrappers are the closures and wrappers folded into the function, only filled with WithFoldWrappers.
+	Wrappers []Function
+}
+
+// Size is the code and pclntab size of the function, without the wrappers.
+func (f Function) Size() uint64 {
+	return f.CodeSize + f.PclnSize
+}
+
+// FullSize is the size of the function along with the wrappers folded into it.
+func (f Function) FullSize() uint64 {
+	size := f.Size()
+	for _, w := range f.Wrappers {
+		size += w.FullSize()
+	}
+	return size
+}
+
+type Symbol struct {
+	Name string
+	Type string
+	Addr uint64
+	Size uint64
+}
+
+// Module is a Go module with the packages it owns, the std and unknown
+// modules hold the standard library and the packages without a module.
+type Module struct {
+	Path string
+	// Version is the version of the replacement if the module is replaced.
+	Version string
+	// Replace is the path of the replacement module, empty if not replaced.
+	Replace string
+	Type    string
+	// Packages are the names of the owned packages, sorted.
+	Packages []string
+	Size     uint64
+}
+
+// Generic is a generic function of a package with its instantiations.
+type Generic struct {
+	Package string
+	// Name is the function name without the type arguments,
+	// methods are qualified by the receiver, e.g. (*List).Push.
+	Name string
+	// Size is the total size of the instantiations.
+	Size uint64
+	// Instances are sorted by size, the largest first.
+	Instances []Instance
+}
+
+// Instance is an instantiation of a generic function.
+type Instance struct {
+	// TypeArgs are the type arguments as the linker names them, e.g. go.shape.int,string.
+	TypeArgs string
+	// Shape is set for the instantiations shared by the types of the same underlying shape.
+	Shape bool
+	Addr  uint64
+	Size  uint64
+}
+
+// Inline is the code of a function inlined into the functions of another package.
+type Inline struct {
+	// Package is the package the code is inlined into.
+	Package string
+	// Function is the inlined function, qualified by its package.
+	Function string
+	// Origin is the package of the inlined function.
+	Origin string
+	Calls  int
+	Size   uint64
+}
+
+// Import is an import of the package To by the package From.
+type Import struct {
+	From string
+	To   string
+	// Uses marks a use found by WithCalls, From calls To directly,
+	// the edges are uses if no import is found.
+	Uses bool
+}
+
+type Section struct {
+	Name string
+
+	Size     uint64
+	FileSize uint64
+	// KnownSize is the part of FileSize attributed to packages.
+	KnownSize uint64
+
+	Offset  uint64
+	End     uint64
+	Addr    uint64
+	AddrEnd uint64
+
+	OnlyInMemory bool
+	Debug        bool
+}
+
+// Name is the base name of the binary.
+func (r *Result) Name() string {
+	return r.raw.Name
+}
+
+// Size is the size of the binary file.
+func (r *Result) Size() uint64 {
+	return r.raw.Size
+}
+
+// Analyzers lists the passes that contributed to the result.
+func (r *Result) Analyzers() []string {
+	return slices.Clone(r.raw.Analyzers)
+}
+
+// Packages returns the top level packages sorted by name, nested ones are in Package.SubPackages.
+func (r *Result) Packages() []Package {
+	return newPackages(r.raw.Packages)
+}
+
+// Modules returns the modules sorted by path, it is only filled with WithModules.
+func (r *Result) Modules() []Module {
+	ret := make([]Module, len(r.raw.Modules))
+	for i, m := range r.raw.Modules {
+		ret[i] = Module{
+			Path:     m.Path,
+			Version:  m.Version,
+			Replace:  m.Replace,
+			Type:     m.Type,
+			Packages: slices.Clone(m.Packages),
+			Size:     m.Size,
+		}
+	}
+	return ret
+}
+
+// Generics returns the generic functions sorted by size, the largest first,
+// it is only filled with WithGenerics.
+func (r *Result) Generics() []Generic {
+	ret := make([]Generic, len(r.raw.Generics))
+	for i, g := range r.raw.Generics {
+		instances := make([]Instance, len(g.Instances))
+		for j, inst := range g.Instances {
+			instances[j] = Instance{TypeArgs: inst.TypeArgs, Shape: inst.Shape, Addr: inst.Addr, Size: inst.Size}
+		}
+		ret[i] = Generic{Package: g.Package, Name: g.Name, Size: g.Size, Instances: instances}
+	}
+	return ret
+}
+
+// Inlines returns the functions inlined into other packages sorted by size,
+// the largest first, it is only filled with WithInlines.
+func (r *Result) Inlines() []Inline {
+	ret := make([]Inline, len(r.raw.Inlines))
+	for i, in := range r.raw.Inlines {
+		ret[i] = Inline{Package: in.Package, Function: in.Function, Origin: in.Origin, Calls: in.Calls, Size: in.Size}
+	}
+	return ret
+}
+
+// Imports returns the import graph as an edge list sorted by From then To,
+// it is only filled with WithImports, or with WithCalls from the uses.
+func (r *Result) Imports() []Import {
+	ret := make([]Import, len(r.raw.Imports))
+	for i, e := range r.raw.Imports {
+		ret[i] = Import{From: e.From, To: e.To, Uses: e.Uses}
+	}
+	return ret
+}
+
+// Sections returns the sections sorted by name.
+func (r *Result) Sections() []Section {
+	ret := make([]Section, len(r.raw.Sections))
+	for i, s := range r.raw.Sections {
+		ret[i] = Section{
+			Name:         s.Name,
+			Size:         s.Size,
+			FileSize:     s.FileSize,
+			KnownSize:    s.KnownSize,
+			Offset:       s.Offset,
+			End:          s.End,
+			Addr:         s.Addr,
+			AddrEnd:      s.AddrEnd,
+			OnlyInMemory: s.OnlyInMemory,
+			Debug:        s.Debug,
+		}
+	}
+	slices.SortFunc(ret, func(a, b Section) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return ret
+}
+
+func newPackages(pkgs entity.PackageMap) []Package {
+	ret := make([]Package, 0, len(pkgs))
+	for _, k := range utils.SortedKeys(pkgs) {
+		ret = append(ret, newPackage(pkgs[k]))
+	}
+	return ret
+}
+
+func newFunctions(fns []*entity.Function) []Function {
+	ret := make([]Function, len(fns))
+	for i, fn := range fns {
+		ret[i] = Function{
+			Name:     fn.Name,
+			Receiver: fn.Receiver,
+			Addr:     fn.Addr,
+			CodeSize: fn.CodeSize,
+			PclnSize: fn.PclnSize.Size(),
+			Calls:    slices.Clone(fn.Calls),
+		}
+		if len(fn.Wrappers) > 0 {
+			ret[i].Wrappers = newFunctions(fn.Wrappers)
+		}
+	}
+	return ret
+}
+
+func newPackage(p *entity.Package) Package {
+	ret := Package{
+		Name:        p.Name,
+		Type:        p.Type,
+		Size:        p.Size,
+		SubPackages: newPackages(p.SubPackages),
+		Files:       make([]SourceFile, len(p.Files)),
+		Symbols:     make([]Symbol, len(p.Symbols)),
+		ImportedBy:  slices.Clone(p.ImportedBy),
+		UsedBy:      slices.Clone(p.UsedBy),
+		Retained:    p.Retained,
+	}
+
+	for i, f := range p.Files {
+		ret.Files[i] = SourceFile{Path: f.FilePath, Functions: newFunctions(f.Functions)}
+	}
+
+	for i, s := range p.Symbols {
+		ret.Symbols[i] = Symbol{
+			Name: s.Name,
+			Type: s.Type,
+			Addr: s.Addr,
+			Size: s.Size,
+		}
+	}
+
+	return ret
+}
@@ -13,16 +13,26 @@ import (
 )
 
 var Options struct {
-	Verbose bool   `help:"Verbose output"`
-	Format  *string `short:"f" enum:"text,json,html,svg" help:"Output format: text|json|html|svg. If omitted, inferred from -o extension (.txt/.json/.html/.svg); otherwise text."`
+	Verbose  bool    `help:"Verbose output"`
+	Progress bool    `help:"Show analysis progress when stderr is a terminal"`
+	Format   *string `short:"f" enum:"text,json,html,svg,markdown,pprof,folded,dot" help:"Output format: text|json|html|svg|markdown|pprof|folded|dot. If omitted, inferred from -o extension (.txt/.json/.html/.svg/.md/.pb.gz/.folded/.dot); otherwise text."`
 
 	NoDisasm bool `help:"Skip disassembly pass"`
 	NoSymbol bool `help:"Skip symbol pass"`
 	NoDwarf  bool `help:"Skip dwarf pass"`
 
+	Modules      bool `help:"Aggregate the packages by their owning module"`
+	FoldWrappers bool `help:"Fold closures, method values, go and defer wrappers and autogenerated method wrappers into the function they belong to"`
+	Inlines      bool `help:"Report the code inlined from other packages, read from the DWARF"`
+
+	DebugFile string   `type:"path" help:"Load the DWARF from a separate debug file or .dSYM bundle" placeholder:"PATH"`
+	DebugDir  []string `type:"path" help:"Look up the debug files by build id or debug link in the directory, /usr/lib/debug is always searched" placeholder:"DIR"`
+	Arch      string   `help:"Analyze the slice of the architecture of a universal Mach-O binary, every slice is analyzed into a combined report without it" placeholder:"ARCH"`
+
 	HideSections bool `help:"Hide sections" group:"text"`
 	HideMain     bool `help:"Hide main package" group:"text"`
 	HideStd      bool `help:"Hide standard library" group:"text"`
+	Generics     bool `help:"Show the largest generic functions with their instantiations" group:"text"`
 
 	Indent  *int `help:"Indentation for json output" group:"json"`
 	Compact bool `help:"Hide function details, replacement with size" group:"json"`
@@ -33,6 +43,9 @@ var Options struct {
 	PaddingBox  int `help:"Padding between box border and content" default:"4" group:"svg"`
 	PaddingRoot int `help:"Padding around root content" default:"32" group:"svg"`
 
+	HideGenerated bool   `help:"Hide generated packages" group:"dot"`
+	MinSize       string `help:"Hide the packages smaller than the size, e.g. 10KB" placeholder:"SIZE" group:"dot"`
+
 	Web         bool                  `long:"web" help:"use web interface to explore the details" group:"web"`
 	Listen      string                `long:"listen" help:"listen address" default:":8080" group:"web"`
 	Open        bool                  `long:"open" help:"Open browser" group:"web"`
@@ -40,7 +53,11 @@ var Options struct {
 
 	Tui bool `long:"tui" help:"Use terminal interface to explore the details" group:"tui"`
 
-	Imports bool `long:"imports" help:"Try analyze package imports from source" group:"imports"`
+	Imports bool     `long:"imports" help:"Try analyze package imports from source" group:"imports"`
+	Calls   bool     `long:"calls" help:"Build the package uses graph from the direct calls found by disassembly, used in place of the imports when no source is found" group:"imports"`
+	Why     []string `long:"why" help:"Show the shortest import chains from main to the package, implies --imports without --calls" placeholder:"PACKAGE" group:"imports"`
+
+	Budget string `long:"budget" help:"Check the result against a size budget json file, exit with status 3 on violation" type:"existingfile" group:"budget"`
 
 	Output []string `short:"o" help:"Write to file. Either a single path (format inferred from extension or from -f; -f conflicting with extension is an error), or one or more FORMAT=PATH pairs to emit multiple formats from a single run, e.g. -o json=a.json -o svg=a.svg. Use '-' as PATH for stdout (at most once)."`
 
@@ -80,6 +97,10 @@ func init() {
 				Key:   "svg",
 				Title: "Svg output options",
 			},
+			{
+				Key:   "dot",
+				Title: "Dot output options",
+			},
 			{
 				Key:   "tui",
 				Title: "Terminal interface options",
@@ -88,6 +109,10 @@ func init() {
 				Key:   "imports",
 				Title: "Imports analysis options",
 			},
+			{
+				Key:   "budget",
+				Title: "Size budget options",
+			},
 		}),
 		kong.Vars{
 			"version": gsv.SprintVersion(),
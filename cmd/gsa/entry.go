@@ -4,25 +4,32 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/x/term"
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/browser"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/Zxilly/go-size-analyzer/internal"
+	"github.com/Zxilly/go-size-analyzer/internal/budget"
 	"github.com/Zxilly/go-size-analyzer/internal/diff"
 	"github.com/Zxilly/go-size-analyzer/internal/printer"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/result"
 	"github.com/Zxilly/go-size-analyzer/internal/tui"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 	"github.com/Zxilly/go-size-analyzer/internal/webui"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
+	"github.com/Zxilly/go-size-analyzer/internal/wrapper"
 )
 
 type outputSpec struct {
@@ -32,7 +39,12 @@ type outputSpec struct {
 }
 
 func inferFormatFromPath(path string) string {
-	switch strings.ToLower(filepath.Ext(path)) {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".pb.gz") {
+		return printer.FormatPprof
+	}
+
+	switch filepath.Ext(lower) {
 	case ".txt":
 		return printer.FormatText
 	case ".json":
@@ -41,6 +53,14 @@ func inferFormatFromPath(path string) string {
 		return printer.FormatHTML
 	case ".svg":
 		return printer.FormatSVG
+	case ".md", ".markdown":
+		return printer.FormatMarkdown
+	case ".pprof":
+		return printer.FormatPprof
+	case ".folded":
+		return printer.FormatFolded
+	case ".dot", ".gv":
+		return printer.FormatDot
 	}
 	return ""
 }
@@ -155,7 +175,16 @@ func closeAll(specs []outputSpec) {
 	}
 }
 
-func renderOne(spec outputSpec, r *result.Result, common printer.CommonOption) error {
+func renderOne(spec outputSpec, r *result.Result, common printer.CommonOption, minSize uint64) error {
+	// the formats drawing the package tree draw the modules as its top level
+	tree := r
+	if common.Modules {
+		switch spec.format {
+		case printer.FormatHTML, printer.FormatSVG, printer.FormatPprof, printer.FormatFolded:
+			tree = r.ModuleTree()
+		}
+	}
+
 	switch spec.format {
 	case printer.FormatText:
 		return printer.Text(r, spec.writer, &common)
@@ -165,9 +194,9 @@ func renderOne(spec outputSpec, r *result.Result, common printer.CommonOption) e
 			HideDetail: Options.Compact,
 		})
 	case printer.FormatHTML:
-		return printer.HTML(r, spec.writer)
+		return printer.HTML(tree, spec.writer)
 	case printer.FormatSVG:
-		return printer.Svg(r, spec.writer, &printer.SvgOption{
+		return printer.Svg(tree, spec.writer, &printer.SvgOption{
 			CommonOption: common,
 			Width:        Options.Width,
 			Height:       Options.Height,
@@ -175,20 +204,143 @@ func renderOne(spec outputSpec, r *result.Result, common printer.CommonOption) e
 			PaddingBox:   Options.PaddingBox,
 			PaddingRoot:  Options.PaddingRoot,
 		})
+	case printer.FormatMarkdown:
+		return printer.Markdown(r, spec.writer, &common)
+	case printer.FormatPprof:
+		return printer.Pprof(tree, spec.writer)
+	case printer.FormatFolded:
+		return printer.Folded(tree, spec.writer, &common)
+	case printer.FormatDot:
+		return printer.Dot(r, spec.writer, &printer.DotOption{
+			CommonOption:  common,
+			HideGenerated: Options.HideGenerated,
+			MinSize:       minSize,
+		})
 	default:
 		return fmt.Errorf("invalid format: %s", spec.format)
 	}
 }
 
+// entryFat analyzes every slice of a universal binary into the combined report.
+func entryFat(ctx context.Context, reader utils.BinaryFile, options internal.Options, specs []outputSpec) error {
+	switch {
+	case Options.Tui || Options.Web:
+		return errors.New("--tui and --web show a single slice of a universal binary, choose one with --arch")
+	case len(Options.Why) > 0:
+		return errors.New("--why is not supported for a universal binary, choose a slice with --arch")
+	case Options.Budget != "":
+		return errors.New("--budget is not supported for a universal binary, choose a slice with --arch")
+	}
+	for _, spec := range specs {
+		switch spec.format {
+		case printer.FormatText, printer.FormatMarkdown, printer.FormatJSON:
+		default:
+			return fmt.Errorf("the %s format shows a single slice of a universal binary, choose one with --arch", spec.format)
+		}
+	}
+
+	fat, err := internal.AnalyzeFat(ctx, Options.Binary, reader, uint64(reader.Len()), options)
+	if err != nil {
+		return fmt.Errorf("analyze %s: %w", Options.Binary, err)
+	}
+
+	common := printer.CommonOption{
+		HideSections: Options.HideSections,
+		HideMain:     Options.HideMain,
+		HideStd:      Options.HideStd,
+		Modules:      Options.Modules,
+	}
+	for _, spec := range specs {
+		switch spec.format {
+		case printer.FormatText:
+			err = printer.FatText(fat, spec.writer, &common)
+		case printer.FormatMarkdown:
+			err = printer.FatMarkdown(fat, spec.writer, &common)
+		case printer.FormatJSON:
+			err = printer.JSON(fat, spec.writer, &printer.JSONOption{
+				Indent:     Options.Indent,
+				HideDetail: Options.Compact,
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.Info("Printing done")
+	return nil
+}
+
 func entry() error {
 	options := internal.Options{
 		SkipSymbol: Options.NoSymbol,
 		SkipDisasm: Options.NoDisasm,
 		SkipDwarf:  Options.NoDwarf,
-		Imports:    Options.Imports,
+		Imports:    Options.Imports || (len(Options.Why) > 0 && !Options.Calls),
+		Calls:      Options.Calls,
+
+		FoldWrappers: Options.FoldWrappers,
+		Modules:      Options.Modules,
+		Generics:     Options.Generics,
+		Inlines:      Options.Inlines,
+
+		DebugFile: Options.DebugFile,
+		DebugDirs: Options.DebugDir,
+		Arch:      Options.Arch,
+	}
+
+	if Options.Calls && Options.NoDisasm {
+		return errors.New("--calls requires the disassembly, it can not be used with --no-disasm")
+	}
+	if Options.Inlines && Options.NoDwarf {
+		return errors.New("--inlines requires the DWARF, it can not be used with --no-dwarf")
+	}
+	if Options.DebugFile != "" && Options.NoDwarf {
+		return errors.New("--debug-file loads the DWARF, it can not be used with --no-dwarf")
+	}
+
+	// an interrupt cancels the analysis, the progress bar is removed before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if Options.Progress && term.IsTerminal(os.Stderr.Fd()) {
+		bar := progress.NewBar(os.Stderr)
+		utils.SyncStderr.SetOutput(bar)
+		defer func() {
+			bar.Done()
+			utils.SyncStderr.SetOutput(os.Stderr)
+		}()
+		options.Progress = bar.Update
+	}
+
+	var budgetConfig *budget.Config
+	if Options.Budget != "" {
+		if Options.Web || Options.Tui {
+			return errors.New("--budget is not supported with --web or --tui")
+		}
+		var err error
+		budgetConfig, err = budget.Load(Options.Budget)
+		if err != nil {
+			return err
+		}
 	}
 
 	if Options.DiffTarget != "" {
+		if Options.Modules {
+			return errors.New("--modules is not supported in diff mode")
+		}
+		if len(Options.Why) > 0 {
+			return errors.New("--why is not supported in diff mode")
+		}
+		if Options.FoldWrappers {
+			return errors.New("--fold-wrappers is not supported in diff mode")
+		}
+		if Options.Inlines {
+			return errors.New("--inlines is not supported in diff mode")
+		}
+		if Options.DebugFile != "" || len(Options.DebugDir) > 0 {
+			return errors.New("--debug-file and --debug-dir are not supported in diff mode")
+		}
 		for _, o := range Options.Output {
 			if strings.Contains(o, "=") {
 				return errors.New("diff mode does not accept FORMAT=PATH -o values")
@@ -211,12 +363,13 @@ func entry() error {
 			writer = spec.writer
 			format = spec.format
 		}
-		return diff.Diff(writer, diff.Options{
+		return diff.Diff(ctx, writer, diff.Options{
 			Options:   options,
 			OldTarget: Options.Binary,
 			NewTarget: Options.DiffTarget,
 			Format:    format,
 			Indent:    Options.Indent,
+			Budget:    budgetConfig,
 		})
 	}
 
@@ -235,12 +388,28 @@ func entry() error {
 		specs = []outputSpec{{format: printer.FormatHTML, writer: webBuf}}
 	}
 
+	var minSize uint64
+	if Options.MinSize != "" {
+		minSize, err = humanize.ParseBytes(Options.MinSize)
+		if err != nil {
+			return fmt.Errorf("invalid --min-size %q: %w", Options.MinSize, err)
+		}
+	}
+
 	reader, err := utils.OpenBinary(Options.Binary)
 	if err != nil {
 		return fmt.Errorf("open binary %s: %w", Options.Binary, err)
 	}
 
-	r, err := internal.Analyze(Options.Binary,
+	if fat, ok := wrapper.FatSlices(reader); ok && Options.Arch == "" && len(fat) > 1 {
+		err = entryFat(ctx, reader, options, specs)
+		if closeErr := reader.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("close %s: %w", Options.Binary, closeErr)
+		}
+		return err
+	}
+
+	r, err := internal.AnalyzeContext(ctx, Options.Binary,
 		reader,
 		uint64(reader.Len()),
 		options)
@@ -252,11 +421,21 @@ func entry() error {
 		return fmt.Errorf("close %s: %w", Options.Binary, err)
 	}
 
+	if len(Options.Why) > 0 {
+		r.Why, err = why.Query(r.Packages, Options.Why)
+		if err != nil {
+			return err
+		}
+	}
+
 	if Options.Tui {
 		w, h, err := term.GetSize(os.Stdout.Fd())
 		if err != nil {
 			return fmt.Errorf("failed to get terminal size: %w", err)
 		}
+		if Options.Modules {
+			r = r.ModuleTree()
+		}
 		return tui.RunTUI(r, w, h)
 	}
 
@@ -264,16 +443,18 @@ func entry() error {
 		HideSections: Options.HideSections,
 		HideMain:     Options.HideMain,
 		HideStd:      Options.HideStd,
+		Modules:      Options.Modules,
+		Generics:     Options.Generics,
 	}
 
 	if len(specs) == 1 {
-		if err := renderOne(specs[0], r, common); err != nil {
+		if err := renderOne(specs[0], r, common, minSize); err != nil {
 			return err
 		}
 	} else {
 		var eg errgroup.Group
 		for _, spec := range specs {
-			eg.Go(func() error { return renderOne(spec, r, common) })
+			eg.Go(func() error { return renderOne(spec, r, common, minSize) })
 		}
 		if err := eg.Wait(); err != nil {
 			return err
@@ -282,6 +463,12 @@ func entry() error {
 
 	slog.Info("Printing done")
 
+	if budgetConfig != nil {
+		if err := budget.Report(utils.SyncStderr, budgetConfig.Check(budget.FromResult(r))); err != nil {
+			return err
+		}
+	}
+
 	if Options.Web {
 		slog.Debug("Starting web server")
 
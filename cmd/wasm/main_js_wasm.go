@@ -10,6 +10,7 @@ import (
 
 	"github.com/Zxilly/go-size-analyzer/internal"
 	"github.com/Zxilly/go-size-analyzer/internal/printer/wasm"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
 
@@ -23,9 +24,18 @@ func analyze(_ js.Value, args []js.Value) any {
 
 	reader := bytes.NewReader(data)
 
-	result, err := internal.Analyze(name, reader, uint64(length), internal.Options{
+	options := internal.Options{
 		SkipDisasm: true,
-	})
+	}
+	// the optional third argument receives (phase, done, total)
+	if len(args) > 2 && args[2].Type() == js.TypeFunction {
+		onProgress := args[2]
+		options.Progress = func(e progress.Event) {
+			onProgress.Invoke(string(e.Phase), e.Done, e.Total)
+		}
+	}
+
+	result, err := internal.Analyze(name, reader, uint64(length), options)
 	if err != nil {
 		slog.Error(fmt.Sprintf("Error: %v\n", err))
 		return js.ValueOf(nil)
@@ -2,20 +2,25 @@ package internal
 
 import (
 	"cmp"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"maps"
 	"path/filepath"
 	"runtime/debug"
 	"slices"
+	"strings"
 
 	"github.com/ZxillyFork/gore"
 
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
 	"github.com/Zxilly/go-size-analyzer/internal/knowninfo"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/result"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
 	"github.com/Zxilly/go-size-analyzer/internal/wrapper"
 )
 
@@ -25,9 +30,56 @@ type Options struct {
 	SkipDwarf  bool
 
 	Imports bool
+	// Calls builds the package uses graph from the direct calls, it requires the disassembly.
+	Calls bool
+	// FoldWrappers folds the closures and wrappers into the function they belong to.
+	FoldWrappers bool
+	// Modules aggregates the packages by their owning module in the result.
+	Modules bool
+	// Generics groups the instantiations by their generic function in the result.
+	Generics bool
+	// Inlines reports the code inlined from other packages, it requires the DWARF.
+	Inlines bool
+
+	// DebugFile is the debug file holding the DWARF of the binary, e.g. a .debug
+	// file or a .dSYM bundle. Without it the debug file is looked up by the
+	// build id or the debug link in DebugDirs if the binary holds no DWARF.
+	DebugFile string
+	// DebugDirs are searched for the debug files before wrapper.DefaultDebugDirs.
+	DebugDirs []string
+
+	// Arch picks the slice of a universal Mach-O binary by the Go architecture,
+	// it is required if the binary holds more than one.
+	Arch string
+
+	// Progress receives the progress events of the analysis, nil discards them.
+	Progress progress.Func
 }
 
 func Analyze(name string, reader io.ReaderAt, size uint64, options Options) (*result.Result, error) {
+	return AnalyzeContext(context.Background(), name, reader, size, options)
+}
+
+// enterPhase reports the start of phase, or returns the error of ctx if it is done.
+func enterPhase(ctx context.Context, options Options, phase progress.Phase) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.Progress.Report(progress.Event{Phase: phase})
+	return nil
+}
+
+// AnalyzeContext is Analyze that stops with the error of ctx once ctx is done.
+func AnalyzeContext(ctx context.Context, name string, reader io.ReaderAt, size uint64, options Options) (*result.Result, error) {
+	if err := enterPhase(ctx, options, progress.PhaseParse); err != nil {
+		return nil, err
+	}
+
+	reader, size, err := pickSlice(reader, size, options.Arch)
+	if err != nil {
+		return nil, err
+	}
+
 	slog.Info("Parsing binary...")
 
 	file, err := gore.OpenReader(reader)
@@ -46,6 +98,22 @@ func Analyze(name string, reader io.ReaderAt, size uint64, options Options) (*re
 
 		Gore:    file,
 		Wrapper: wrapper.NewWrapper(file.GetParsedFile()),
+
+		Ctx:      ctx,
+		Progress: options.Progress,
+
+		Calls: options.Calls && !options.SkipDisasm,
+	}
+
+	if arch := k.Wrapper.GoArch(); options.Arch != "" && arch != options.Arch {
+		return nil, fmt.Errorf("the binary is for %s, not %s", arch, options.Arch)
+	}
+
+	if options.Calls && options.SkipDisasm {
+		slog.Warn("The call graph requires the disassembly, skipped")
+	}
+	if options.Inlines && options.SkipDwarf {
+		slog.Warn("The inlining report requires the DWARF, skipped")
 	}
 
 	isWasm := file.FileInfo.Arch == "wasm"
@@ -60,22 +128,34 @@ func Analyze(name string, reader io.ReaderAt, size uint64, options Options) (*re
 	slog.Info("Found build info")
 	utils.WaitDebugger("Found build info")
 
+	if err = enterPhase(ctx, options, progress.PhaseSections); err != nil {
+		return nil, err
+	}
 	if err = k.LoadSectionMap(); err != nil {
 		return nil, err
 	}
 
 	k.KnownAddr = entity.NewKnownAddr(k.Sects)
 
+	if err = enterPhase(ctx, options, progress.PhasePackages); err != nil {
+		return nil, err
+	}
 	if err = k.LoadGoreInfo(file, isWasm); err != nil {
 		return nil, err
 	}
 
+	if !isWasm && !options.SkipDwarf {
+		if err = loadDebugFile(k, name, options); err != nil {
+			return nil, err
+		}
+	}
+
 	var sections []*entity.Section
 	var analyzers []entity.Analyzer
 	if isWasm {
-		sections, analyzers, err = analyzeWasm(k, options)
+		sections, analyzers, err = analyzeWasm(ctx, k, options)
 	} else {
-		sections, analyzers, err = analyzeNative(k, options)
+		sections, analyzers, err = analyzeNative(ctx, k, options)
 	}
 	if err != nil {
 		return nil, err
@@ -87,6 +167,22 @@ func Analyze(name string, reader io.ReaderAt, size uint64, options Options) (*re
 
 	slices.Sort(analyzers)
 
+	var imports []why.Edge
+	if options.Imports || k.Calls {
+		g := why.NewGraph(k.Deps.TopPkgs)
+		g.SetRetained()
+		imports = g.Edges()
+	}
+
+	var modules []*entity.Module
+	if options.Modules {
+		modules = k.CollectModules()
+	}
+	var generics []*entity.Generic
+	if options.Generics {
+		generics = k.CollectGenerics()
+	}
+
 	utils.WaitDebugger("Analyze done")
 
 	return &result.Result{
@@ -95,6 +191,10 @@ func Analyze(name string, reader io.ReaderAt, size uint64, options Options) (*re
 		Packages:  k.Deps.TopPkgs,
 		Sections:  sections,
 		Analyzers: analyzers,
+		Modules:   modules,
+		Generics:  generics,
+		Inlines:   k.Inlines,
+		Imports:   imports,
 	}, nil
 }
 
@@ -117,24 +217,37 @@ func runOptionalAnalyzer(
 	return nil
 }
 
-func analyzeWasm(k *knowninfo.KnownInfo, options Options) ([]*entity.Section, []entity.Analyzer, error) {
+func analyzeWasm(ctx context.Context, k *knowninfo.KnownInfo, options Options) ([]*entity.Section, []entity.Analyzer, error) {
+	// The Go linker emits no DWARF for wasm, so there is no DWARF step.
+	if options.Inlines {
+		slog.Warn("The inlining report needs the DWARF, which wasm binaries do not have, skipped")
+	}
+
 	// Gore file is fully consumed after LoadGoreInfo for wasm (no DWARF step).
 	debug.FreeOSMemory()
 	utils.WaitDebugger("After force gc")
 
 	analyzers := []entity.Analyzer{entity.AnalyzerPclntab}
 
+	if err := enterPhase(ctx, options, progress.PhaseTypes); err != nil {
+		return nil, nil, err
+	}
 	if err := runOptionalAnalyzer(k.AnalyzeTypes, entity.AnalyzerTyp, &analyzers,
 		"Type analysis skipped: Go version not available in binary"); err != nil {
 		return nil, nil, err
 	}
+	if err := enterPhase(ctx, options, progress.PhasePclntabMeta); err != nil {
+		return nil, nil, err
+	}
 	if err := runOptionalAnalyzer(k.AnalyzePclntabMeta, entity.AnalyzerPclntabMeta, &analyzers,
 		"Pclntab meta analysis skipped: Go version not available in binary"); err != nil {
 		return nil, nil, err
 	}
 
 	// All analyzers done; materialize the package tree.
-	k.Deps.FinishLoad(options.Imports)
+	if err := finishLoad(ctx, k, options); err != nil {
+		return nil, nil, err
+	}
 	utils.WaitDebugger("All analyzers and deps done")
 	k.Deps.ClearCaches()
 
@@ -142,30 +255,98 @@ func analyzeWasm(k *knowninfo.KnownInfo, options Options) ([]*entity.Section, []
 
 	wasmWrapper := k.Wrapper.(*wrapper.WasmWrapper)
 	codeSectUsed := wasmCodeSectUsed(k)
-	dataSectUsed := wasmWrapper.ComputeDataSectUsed(k.KnownAddr.SymbolAddrSpace)
+	dataSectUsed := wasmWrapper.ComputeDataSectUsed(k.KnownAddr.SymbolAddrSpace) + wasmPclnSize(k)
 	sections := wasmWrapper.GetSections(codeSectUsed, dataSectUsed)
 
 	return sections, analyzers, nil
 }
 
-func analyzeNative(k *knowninfo.KnownInfo, options Options) ([]*entity.Section, []entity.Analyzer, error) {
-	analyzers := []entity.Analyzer{entity.AnalyzerPclntab}
+// hasDebugInfo reports whether the binary holds the DWARF.
+func hasDebugInfo(sects *entity.Store) bool {
+	for name, s := range sects.Sections {
+		if s.Debug && strings.HasSuffix(name, "debug_info") {
+			return true
+		}
+	}
+	return false
+}
 
-	// fixme: add wasm dwarf support
-	if !options.SkipDwarf {
-		slog.Info("Parsing DWARF...")
-		if k.TryLoadDwarf() {
-			analyzers = append(analyzers, entity.AnalyzerDwarf)
-			slog.Info("Parsed DWARF")
-		} else {
-			slog.Warn("DWARF parsing failed, fallback to symbol and disasm")
+// loadDebugFile loads the DWARF from the debug file of options, or from the one
+// found next to the binary or in the debug directories if it holds no DWARF.
+func loadDebugFile(k *knowninfo.KnownInfo, name string, options Options) error {
+	l, ok := k.Wrapper.(wrapper.DebugFileLoader)
+	path := options.DebugFile
+	if path == "" {
+		if !ok || hasDebugInfo(k.Sects) {
+			return nil
+		}
+		path = l.FindDebugFile(name, slices.Concat(options.DebugDirs, wrapper.DefaultDebugDirs))
+		if path == "" {
+			return nil
 		}
+		slog.Info("Found debug file", "path", path)
+	} else if !ok {
+		return errors.New("debug files are only supported for ELF and Mach-O binaries")
+	}
+
+	if err := l.LoadDebugFile(path); err != nil {
+		if options.DebugFile == "" {
+			slog.Warn("Skipped debug file", "path", path, "err", err)
+			return nil
+		}
+		return fmt.Errorf("load debug file %s: %w", path, err)
+	}
+	slog.Info("Loaded DWARF from debug file", "path", path)
+	return nil
+}
+
+// loadDwarf runs the DWARF pass and appends its tag to analyzers on success,
+// a binary without DWARF falls back to the other passes.
+func loadDwarf(ctx context.Context, k *knowninfo.KnownInfo, options Options, analyzers *[]entity.Analyzer) error {
+	if options.SkipDwarf {
+		return nil
+	}
+	if err := enterPhase(ctx, options, progress.PhaseDwarf); err != nil {
+		return err
+	}
+
+	slog.Info("Parsing DWARF...")
+	if !k.TryLoadDwarf() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		slog.Warn("DWARF parsing failed, fallback to symbol and disasm")
+		return nil
+	}
+	*analyzers = append(*analyzers, entity.AnalyzerDwarf)
+	slog.Info("Parsed DWARF")
+
+	if options.Inlines {
+		if err := k.LoadDwarfInlines(); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			slog.Warn("Failed to load DWARF inlined subroutines", "err", err)
+		}
+	}
+	return nil
+}
+
+func analyzeNative(ctx context.Context, k *knowninfo.KnownInfo, options Options) ([]*entity.Section, []entity.Analyzer, error) {
+	analyzers := []entity.Analyzer{entity.AnalyzerPclntab}
+
+	if err := loadDwarf(ctx, k, options, &analyzers); err != nil {
+		return nil, nil, err
 	}
 
 	// Gore file is fully consumed after DWARF parsing.
 	debug.FreeOSMemory()
 	utils.WaitDebugger("After force gc")
 
+	if err := enterPhase(ctx, options, progress.PhaseSymbol); err != nil {
+		return nil, nil, err
+	}
+
 	// fixme: add data symbol support to go gc
 	record := !options.SkipSymbol
 	if err := k.AnalyzeSymbol(record); err != nil {
@@ -179,16 +360,25 @@ func analyzeNative(k *knowninfo.KnownInfo, options Options) ([]*entity.Section,
 	}
 	utils.WaitDebugger("Symbol done")
 
+	if err := enterPhase(ctx, options, progress.PhaseTypes); err != nil {
+		return nil, nil, err
+	}
 	if err := runOptionalAnalyzer(k.AnalyzeTypes, entity.AnalyzerTyp, &analyzers,
 		"Type analysis skipped: Go version not available in binary"); err != nil {
 		return nil, nil, err
 	}
+	if err := enterPhase(ctx, options, progress.PhasePclntabMeta); err != nil {
+		return nil, nil, err
+	}
 	if err := runOptionalAnalyzer(k.AnalyzePclntabMeta, entity.AnalyzerPclntabMeta, &analyzers,
 		"Pclntab meta analysis skipped: Go version not available in binary"); err != nil {
 		return nil, nil, err
 	}
 
 	if !options.SkipDisasm {
+		if err := enterPhase(ctx, options, progress.PhaseDisasm); err != nil {
+			return nil, nil, err
+		}
 		if k.GoStringSymbol == nil {
 			slog.Info("no go:string.* symbol found, false-positive rates may rise")
 		}
@@ -201,7 +391,9 @@ func analyzeNative(k *knowninfo.KnownInfo, options Options) ([]*entity.Section,
 
 	// DWARF, symbol, type, pclntab-meta, and disasm analyzers can all create
 	// new packages, so materialize the package tree only after they have all completed.
-	k.Deps.FinishLoad(options.Imports)
+	if err := finishLoad(ctx, k, options); err != nil {
+		return nil, nil, err
+	}
 	utils.WaitDebugger("All analyzers and deps done")
 	k.Deps.ClearCaches()
 
@@ -217,6 +409,26 @@ func analyzeNative(k *knowninfo.KnownInfo, options Options) ([]*entity.Section,
 	return sections, analyzers, nil
 }
 
+// finishLoad folds the wrappers and materializes the package tree with the
+// imports if requested, then enters the coverage phase.
+func finishLoad(ctx context.Context, k *knowninfo.KnownInfo, options Options) error {
+	if options.Imports {
+		if err := enterPhase(ctx, options, progress.PhaseImports); err != nil {
+			return err
+		}
+	}
+	if options.FoldWrappers {
+		k.FoldWrappers()
+	}
+	if err := k.Deps.FinishLoad(options.Imports); err != nil {
+		return err
+	}
+	if k.Calls {
+		k.Deps.UpdateUsedBy()
+	}
+	return enterPhase(ctx, options, progress.PhaseCoverage)
+}
+
 // wasmCodeSectUsed sums the code size of all functions across all packages,
 // used to compute KnownSize for the Wasm code section.
 func wasmCodeSectUsed(k *knowninfo.KnownInfo) uint64 {
@@ -229,3 +441,41 @@ func wasmCodeSectUsed(k *knowninfo.KnownInfo) uint64 {
 	})
 	return total
 }
+
+// wasmPclnSize sums the pcln size of the functions, the pclntab is in the data
+// section of wasm. The sum over-counts the tables shared by several functions,
+// so it is bounded by the part of the pclntab not covered by data symbols.
+func wasmPclnSize(k *knowninfo.KnownInfo) uint64 {
+	total := uint64(0)
+	_ = k.Deps.Trie.Walk(func(_ string, pkg *entity.Package) error {
+		for f := range pkg.Functions {
+			total += f.PclnSize.Size()
+		}
+		return nil
+	})
+
+	md, err := k.Gore.Moduledata()
+	if err != nil {
+		slog.Warn("Failed to bound the wasm pcln size by the pclntab", "err", err)
+		return total
+	}
+	pclntab := md.PCLNTab()
+	return min(total, pclntabUncovered(k.KnownAddr.SymbolAddrSpace, pclntab.Address, pclntab.Length))
+}
+
+// pclntabUncovered returns the bytes of the pclntab at [addr, addr+length) not
+// covered by the data symbols, e.g. the functab, cutab and filetab symbols.
+func pclntabUncovered(symbols entity.AddrSpace, addr, length uint64) uint64 {
+	end := addr + length
+	covered := uint64(0)
+	for _, a := range symbols {
+		if a.Type != entity.AddrTypeData {
+			continue
+		}
+		lo, hi := max(a.Addr, addr), min(a.Addr+a.Size, end)
+		if lo < hi {
+			covered += hi - lo
+		}
+	}
+	return length - min(covered, length)
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/ZxillyFork/gore"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/test/testutils"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 
@@ -62,6 +64,109 @@ func TestAnalyzeImports(t *testing.T) {
 	require.Contains(t, testingPkg.ImportedBy, "github.com/Zxilly/go-size-analyzer/internal")
 }
 
+func TestAnalyzeModules(t *testing.T) {
+	bin := GetCurrentRunningBinary(t)
+
+	f, err := utils.OpenBinary(bin)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	result, err := Analyze(bin, f, uint64(f.Len()), Options{
+		SkipDisasm: true,
+		Modules:    true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Generics)
+
+	modules := make(map[string]*entity.Module)
+	for _, m := range result.Modules {
+		modules[m.Path] = m
+	}
+
+	main := modules["github.com/Zxilly/go-size-analyzer"]
+	require.NotNil(t, main)
+	require.Equal(t, entity.PackageTypeMain, main.Type)
+	require.Contains(t, main.Packages, "github.com/Zxilly/go-size-analyzer/internal")
+
+	testify := modules["github.com/stretchr/testify"]
+	require.NotNil(t, testify)
+	require.Equal(t, entity.PackageTypeVendor, testify.Type)
+	require.NotEmpty(t, testify.Version)
+	require.Contains(t, testify.Packages, "github.com/stretchr/testify/require")
+	require.NotZero(t, testify.Size)
+
+	std := modules[entity.ModulePathStd]
+	require.NotNil(t, std)
+	require.Contains(t, std.Packages, "runtime")
+}
+
+func TestAnalyzeContextProgress(t *testing.T) {
+	bin := GetCurrentRunningBinary(t)
+
+	f, err := utils.OpenBinary(bin)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	var events []progress.Event
+	result, err := AnalyzeContext(context.Background(), bin, f, uint64(f.Len()), Options{
+		SkipDwarf: true,
+		Imports:   true,
+		Progress: func(e progress.Event) {
+			events = append(events, e)
+		},
+	})
+	require.NoError(t, err)
+
+	// the modules and the generics are only collected on request
+	require.Empty(t, result.Modules)
+	require.Empty(t, result.Generics)
+
+	require.Equal(t, progress.Event{Phase: progress.PhaseParse}, events[0])
+	require.Equal(t, progress.Event{Phase: progress.PhaseCoverage}, events[len(events)-1])
+
+	for _, phase := range []progress.Phase{progress.PhaseDisasm, progress.PhaseImports} {
+		var last progress.Event
+		for _, e := range events {
+			if e.Phase == phase {
+				require.GreaterOrEqual(t, e.Done, last.Done)
+				last = e
+			}
+		}
+		require.NotZero(t, last.Total, phase)
+		require.Equal(t, last.Total, last.Done, phase)
+	}
+}
+
+func TestAnalyzeContextCanceled(t *testing.T) {
+	bin := GetCurrentRunningBinary(t)
+
+	f, err := utils.OpenBinary(bin)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var phases []progress.Phase
+	_, err = AnalyzeContext(ctx, bin, f, uint64(f.Len()), Options{
+		SkipDwarf: true,
+		Progress: func(e progress.Event) {
+			phases = append(phases, e.Phase)
+			if e.Phase == progress.PhaseDisasm && e.Done > 0 {
+				cancel()
+			}
+		},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotContains(t, phases, progress.PhaseCoverage)
+}
+
 func TestAnalyzeWASM(t *testing.T) {
 	loc := filepath.Join(testutils.GetProjectRoot(t), "testdata", "wasm", "test.wasm")
 	data, err := os.ReadFile(loc)
@@ -82,7 +187,7 @@ func TestAnalyzeWASM(t *testing.T) {
 	}
 }
 
-func TestAnalyzeWASMPclntabFullyAttributed(t *testing.T) {
+func TestAnalyzeWASMPclnSize(t *testing.T) {
 	loc := filepath.Join(testutils.GetProjectRoot(t), "testdata", "wasm", "test.wasm")
 	data, err := os.ReadFile(loc)
 	require.NoError(t, err)
@@ -97,7 +202,49 @@ func TestAnalyzeWASMPclntabFullyAttributed(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Contains(t, result.Analyzers, entity.AnalyzerPclntabMeta)
-	require.Equal(t, md.PCLNTab().Length, sumSymbolSizesWithPrefix(result.Packages, "pclntab:"))
+
+	// the pc tables are charged to the functions, not spread by the function count
+	for fn := range result.Packages["main"].Functions {
+		require.NotZerof(t, fn.PclnSize.PCLN, "function %s has no pc to line table", fn.Name)
+	}
+
+	// the functions and the functab, cutab and filetab symbols share the pclntab
+	// without overlapping. They do not add up to it exactly, the header is not
+	// attributed and the per-function sizes approximate the _func layout, while
+	// a table shared by several functions counts for each of them.
+	known := sumPclnSizes(result.Packages) + sumSymbolSizesWithPrefix(result.Packages, "pclntab:")
+	require.LessOrEqual(t, known, md.PCLNTab().Length)
+	require.Greater(t, known, md.PCLNTab().Length*3/4)
+}
+
+func TestPclntabUncovered(t *testing.T) {
+	symbols := make(entity.AddrSpace)
+	for _, pos := range []entity.AddrPos{
+		{Addr: 0xf00, Size: 0x200, Type: entity.AddrTypeData},  // starts before the pclntab
+		{Addr: 0x1400, Size: 0x100, Type: entity.AddrTypeData}, // inside
+		{Addr: 0x1f80, Size: 0x100, Type: entity.AddrTypeData}, // ends after it
+		{Addr: 0x1800, Size: 0x100, Type: entity.AddrTypeText},
+		{Addr: 0x3000, Size: 0x100, Type: entity.AddrTypeData},
+	} {
+		symbols.Insert(&entity.Addr{AddrPos: &pos})
+	}
+
+	require.Equal(t, uint64(0x1000-0x100-0x100-0x80), pclntabUncovered(symbols, 0x1000, 0x1000))
+	require.Equal(t, uint64(0x1000), pclntabUncovered(nil, 0x1000, 0x1000))
+
+	symbols.Insert(&entity.Addr{AddrPos: &entity.AddrPos{Addr: 0x1000, Size: 0x1000, Type: entity.AddrTypeData}})
+	require.Zero(t, pclntabUncovered(symbols, 0x1000, 0x1000))
+}
+
+func sumPclnSizes(pkgs entity.PackageMap) uint64 {
+	total := uint64(0)
+	for _, pkg := range pkgs {
+		for fn := range pkg.Functions {
+			total += fn.PclnSize.Size()
+		}
+		total += sumPclnSizes(pkg.SubPackages)
+	}
+	return total
 }
 
 func countSymbols(pkgs entity.PackageMap) int {
@@ -121,3 +268,39 @@ func sumSymbolSizesWithPrefix(pkgs entity.PackageMap, prefix string) uint64 {
 	}
 	return total
 }
+
+func TestPickSlice(t *testing.T) {
+	// a universal binary of amd64 at 0x10 and arm64 at 0x20, without the Mach-O files
+	data := make([]byte, 0x30)
+	copy(data, []byte{
+		0xca, 0xfe, 0xba, 0xbe, 0, 0, 0, 2,
+		0x01, 0, 0, 0x07, 0, 0, 0, 0, 0, 0, 0, 0x10, 0, 0, 0, 0x10, 0, 0, 0, 0x04,
+		0x01, 0, 0, 0x0c, 0, 0, 0, 0, 0, 0, 0, 0x20, 0, 0, 0, 0x10, 0, 0, 0, 0x04,
+	})
+	data[0x20] = 0xaa
+
+	r, size, err := pickSlice(bytes.NewReader(data), uint64(len(data)), "arm64")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x10), size)
+	b := make([]byte, 1)
+	_, err = r.ReadAt(b, 0)
+	require.NoError(t, err)
+	require.Equal(t, byte(0xaa), b[0])
+
+	_, _, err = pickSlice(bytes.NewReader(data), uint64(len(data)), "")
+	require.ErrorIs(t, err, ErrFatArch)
+	require.ErrorContains(t, err, "amd64, arm64")
+
+	_, _, err = pickSlice(bytes.NewReader(data), uint64(len(data)), "386")
+	require.ErrorIs(t, err, ErrFatArch)
+
+	// other binaries are left alone
+	thin := bytes.NewReader([]byte("\x7fELF"))
+	r, size, err = pickSlice(thin, 4, "arm64")
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), size)
+	require.Equal(t, thin, r)
+
+	_, err = AnalyzeFat(context.Background(), "bin", thin, 4, Options{})
+	require.ErrorIs(t, err, ErrNotFat)
+}
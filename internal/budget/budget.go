@@ -0,0 +1,187 @@
+package budget
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"encoding/json/v2"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// ExitCode is the process exit status when a budget is exceeded,
+// distinct from the status 1 used for analysis errors.
+const ExitCode = 3
+
+// Size is a byte count, decoded from a number or a human-readable string like "10MB" or "1.5 MiB".
+type Size int64
+
+func (s *Size) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var str string
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+		v, err := humanize.ParseBytes(str)
+		if err != nil {
+			return fmt.Errorf("invalid size %q: %w", str, err)
+		}
+		*s = Size(v)
+		return nil
+	}
+
+	var v int64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("invalid size %d: must not be negative", v)
+	}
+	*s = Size(v)
+	return nil
+}
+
+// Growth limits how much the new binary may grow in diff mode.
+type Growth struct {
+	MaxBytes   Size    `json:"max_bytes"`
+	MaxPercent float64 `json:"max_percent"`
+}
+
+// Config is the budget file, zero values mean no limit.
+type Config struct {
+	MaxSize  Size            `json:"max_size"`
+	Packages map[string]Size `json:"packages"`
+	Sections map[string]Size `json:"sections"`
+	Growth   Growth          `json:"growth"`
+}
+
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read budget %s: %w", path, err)
+	}
+
+	c := new(Config)
+	if err = json.Unmarshal(data, c, json.RejectUnknownMembers(true)); err != nil {
+		return nil, fmt.Errorf("decode budget %s: %w", path, err)
+	}
+	if c.Growth.MaxPercent < 0 {
+		return nil, fmt.Errorf("decode budget %s: growth.max_percent must not be negative", path)
+	}
+	return c, nil
+}
+
+// Sizes is the part of an analysis result a budget is checked against.
+type Sizes struct {
+	Name string
+	Size int64
+
+	// Packages is keyed by the full package path, nested packages included.
+	Packages map[string]int64
+	// Sections holds the file size, keyed by section name.
+	Sections map[string]int64
+}
+
+func FromResult(r *result.Result) Sizes {
+	s := Sizes{
+		Name:     r.Name,
+		Size:     int64(r.Size),
+		Packages: make(map[string]int64),
+		Sections: make(map[string]int64, len(r.Sections)),
+	}
+
+	var walk func(pkgs entity.PackageMap)
+	walk = func(pkgs entity.PackageMap) {
+		for _, p := range pkgs {
+			s.Packages[p.Name] = int64(p.Size)
+			walk(p.SubPackages)
+		}
+	}
+	walk(r.Packages)
+
+	for _, sect := range r.Sections {
+		s.Sections[sect.Name] = int64(sect.FileSize)
+	}
+
+	return s
+}
+
+type Rule string
+
+const (
+	RuleSize          Rule = "size"
+	RulePackage       Rule = "package"
+	RuleSection       Rule = "section"
+	RuleGrowth        Rule = "growth"
+	RuleGrowthPercent Rule = "growth percent"
+)
+
+// Violation is a single exceeded limit, Limit and Actual are in bytes.
+type Violation struct {
+	Rule   Rule
+	Target string
+	Limit  int64
+	Actual int64
+}
+
+func (v Violation) Over() int64 {
+	return v.Actual - v.Limit
+}
+
+// Check evaluates the absolute limits against s.
+func (c *Config) Check(s Sizes) []Violation {
+	var ret []Violation
+
+	if c.MaxSize > 0 && s.Size > int64(c.MaxSize) {
+		ret = append(ret, Violation{Rule: RuleSize, Target: s.Name, Limit: int64(c.MaxSize), Actual: s.Size})
+	}
+
+	check := func(rule Rule, limits map[string]Size, sizes map[string]int64) {
+		for _, name := range utils.SortedKeys(limits) {
+			actual, ok := sizes[name]
+			if !ok {
+				slog.Warn(fmt.Sprintf("Budget %s %s not found in %s", rule, name, s.Name))
+				continue
+			}
+			if limit := int64(limits[name]); actual > limit {
+				ret = append(ret, Violation{Rule: rule, Target: name, Limit: limit, Actual: actual})
+			}
+		}
+	}
+	check(RulePackage, c.Packages, s.Packages)
+	check(RuleSection, c.Sections, s.Sections)
+
+	return ret
+}
+
+// CheckGrowth evaluates the growth limits from oldSizes to newSizes.
+func (c *Config) CheckGrowth(oldSizes, newSizes Sizes) []Violation {
+	var ret []Violation
+
+	growth := newSizes.Size - oldSizes.Size
+	target := fmt.Sprintf("%s -> %s", oldSizes.Name, newSizes.Name)
+
+	if c.Growth.MaxBytes > 0 && growth > int64(c.Growth.MaxBytes) {
+		ret = append(ret, Violation{Rule: RuleGrowth, Target: target, Limit: int64(c.Growth.MaxBytes), Actual: growth})
+	}
+
+	// percent of nothing is meaningless
+	if c.Growth.MaxPercent > 0 && oldSizes.Size > 0 {
+		limit := int64(float64(oldSizes.Size) * c.Growth.MaxPercent / 100)
+		if growth > limit {
+			ret = append(ret, Violation{
+				Rule:   RuleGrowthPercent,
+				Target: fmt.Sprintf("%s (%g%%)", target, c.Growth.MaxPercent),
+				Limit:  limit,
+				Actual: growth,
+			})
+		}
+	}
+
+	return ret
+}
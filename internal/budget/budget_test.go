@@ -0,0 +1,174 @@
+package budget
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "budget.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `{
+		"max_size": "10MB",
+		"packages": {"runtime": 1048576},
+		"sections": {".rodata": "1.5 MiB"},
+		"growth": {"max_bytes": "100kB", "max_percent": 5}
+	}`)
+
+	c, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, Size(10_000_000), c.MaxSize)
+	assert.Equal(t, Size(1048576), c.Packages["runtime"])
+	assert.Equal(t, Size(1572864), c.Sections[".rodata"])
+	assert.Equal(t, Size(100_000), c.Growth.MaxBytes)
+	assert.InDelta(t, 5.0, c.Growth.MaxPercent, 0)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown field", `{"max_sizes": 1}`},
+		{"bad size string", `{"max_size": "ten"}`},
+		{"negative size", `{"max_size": -1}`},
+		{"negative percent", `{"growth": {"max_percent": -1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestFromResult(t *testing.T) {
+	sub := entity.NewPackage()
+	sub.Name = "golang.org/x/sys/unix"
+	sub.Size = 30
+
+	top := entity.NewPackage()
+	top.Name = "golang.org/x/sys"
+	top.Size = 40
+	top.SubPackages["unix"] = sub
+
+	r := &result.Result{
+		Name:     "bin",
+		Size:     1000,
+		Packages: entity.PackageMap{"golang.org/x/sys": top},
+		Sections: []*entity.Section{{Name: ".text", Size: 600, FileSize: 500}},
+	}
+
+	s := FromResult(r)
+	assert.Equal(t, Sizes{
+		Name: "bin",
+		Size: 1000,
+		Packages: map[string]int64{
+			"golang.org/x/sys":      40,
+			"golang.org/x/sys/unix": 30,
+		},
+		Sections: map[string]int64{".text": 500},
+	}, s)
+}
+
+func TestCheck(t *testing.T) {
+	s := Sizes{
+		Name:     "bin",
+		Size:     1000,
+		Packages: map[string]int64{"main": 100, "runtime": 500},
+		Sections: map[string]int64{".text": 600, ".rodata": 200},
+	}
+
+	c := &Config{
+		MaxSize:  900,
+		Packages: map[string]Size{"main": 100, "runtime": 400, "missing": 1},
+		Sections: map[string]Size{".text": 500, ".rodata": 300},
+	}
+
+	assert.Equal(t, []Violation{
+		{Rule: RuleSize, Target: "bin", Limit: 900, Actual: 1000},
+		{Rule: RulePackage, Target: "runtime", Limit: 400, Actual: 500},
+		{Rule: RuleSection, Target: ".text", Limit: 500, Actual: 600},
+	}, c.Check(s))
+
+	assert.Empty(t, (&Config{}).Check(s))
+}
+
+func TestCheckGrowth(t *testing.T) {
+	oldSizes := Sizes{Name: "old", Size: 1000}
+	newSizes := Sizes{Name: "new", Size: 1100}
+
+	tests := []struct {
+		name   string
+		growth Growth
+		want   []Violation
+	}{
+		{"no limit", Growth{}, nil},
+		{"bytes within", Growth{MaxBytes: 100}, nil},
+		{"bytes exceeded", Growth{MaxBytes: 50}, []Violation{
+			{Rule: RuleGrowth, Target: "old -> new", Limit: 50, Actual: 100},
+		}},
+		{"percent within", Growth{MaxPercent: 10}, nil},
+		{"percent exceeded", Growth{MaxPercent: 2.5}, []Violation{
+			{Rule: RuleGrowthPercent, Target: "old -> new (2.5%)", Limit: 25, Actual: 100},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Growth: tt.growth}
+			assert.Equal(t, tt.want, c.CheckGrowth(oldSizes, newSizes))
+		})
+	}
+
+	t.Run("shrink", func(t *testing.T) {
+		c := &Config{Growth: Growth{MaxBytes: 1, MaxPercent: 1}}
+		assert.Empty(t, c.CheckGrowth(newSizes, oldSizes))
+	})
+
+	t.Run("percent of empty", func(t *testing.T) {
+		c := &Config{Growth: Growth{MaxPercent: 1}}
+		assert.Empty(t, c.CheckGrowth(Sizes{Name: "old"}, newSizes))
+	})
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Report(&buf, nil))
+	assert.Empty(t, buf.String())
+
+	violations := []Violation{{Rule: RulePackage, Target: "runtime", Limit: 1000, Actual: 1500}}
+	err := Report(&buf, violations)
+
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, violations, exceeded.Violations)
+
+	var coder utils.ExitCoder
+	require.True(t, errors.As(err, &coder))
+	assert.Equal(t, ExitCode, coder.ExitCode())
+
+	out := buf.String()
+	assert.Contains(t, out, "Size budget exceeded")
+	assert.Contains(t, out, "runtime")
+	assert.Contains(t, out, "1.5 kB")
+	assert.Contains(t, out, "+500 B")
+}
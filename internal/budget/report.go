@@ -0,0 +1,62 @@
+package budget
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// ExceededError is returned when at least one budget is violated,
+// it carries ExitCode for utils.FatalError.
+type ExceededError struct {
+	Violations []Violation
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("size budget exceeded, %d violation(s)", len(e.Violations))
+}
+
+func (e *ExceededError) ExitCode() int {
+	return ExitCode
+}
+
+func signedBytes(b int64) string {
+	if b < 0 {
+		return "-" + humanize.Bytes(uint64(-b))
+	}
+	return humanize.Bytes(uint64(b))
+}
+
+// Report writes the violations as a table and returns an *ExceededError,
+// it writes nothing and returns nil when there is no violation.
+func Report(w io.Writer, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	t.SetTitle("Size budget exceeded")
+	t.AppendHeader(table.Row{"Rule", "Target", "Limit", "Actual", "Over"})
+
+	for _, v := range violations {
+		t.AppendRow(table.Row{
+			v.Rule,
+			v.Target,
+			signedBytes(v.Limit),
+			signedBytes(v.Actual),
+			"+" + signedBytes(v.Over()),
+		})
+	}
+
+	if _, err := w.Write([]byte(t.Render() + "\n")); err != nil {
+		return err
+	}
+
+	return &ExceededError{Violations: violations}
+}
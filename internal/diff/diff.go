@@ -3,43 +3,51 @@ package diff
 import (
 	"cmp"
 	"slices"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
 )
 
-type diffResult struct {
+// Result is the diff between two results, it is also read by the public api.
+type Result struct {
 	OldName string `json:"old_name"`
 	NewName string `json:"new_name"`
 
 	OldSize int64 `json:"old_size"`
 	NewSize int64 `json:"new_size"`
 
-	Packages []diffPackage `json:"packages"`
-	Sections []diffSection `json:"sections"`
+	Packages  []Package  `json:"packages"`
+	Sections  []Section  `json:"sections"`
+	Functions []Function `json:"functions,omitempty"`
+	Symbols   []Symbol   `json:"symbols,omitempty"`
 }
 
-type changeType string
+type ChangeType string
 
 const (
-	changeTypeAdd    changeType = "add"
-	changeTypeRemove changeType = "remove"
-	changeTypeChange changeType = "change"
+	changeTypeAdd    ChangeType = "add"
+	changeTypeRemove ChangeType = "remove"
+	changeTypeChange ChangeType = "change"
 )
 
 type Base struct {
 	Name       string     `json:"name"`
 	From       int64      `json:"from"`
 	To         int64      `json:"to"`
-	ChangeType changeType `json:"change_type"`
+	ChangeType ChangeType `json:"change_type"`
 }
 
 func diffBaseCmp(a, b Base) int {
 	return -cmp.Compare(a.To-a.From, b.To-b.From)
 }
 
-type diffPackage struct {
+type Package struct {
 	Base
+	Type entity.PackageType `json:"type"`
 }
 
-type diffSection struct {
+type Section struct {
 	Base
 	OldFileSize  int64 `json:"old_file_size"`
 	OldKnownSize int64 `json:"old_known_size"`
@@ -47,7 +55,159 @@ type diffSection struct {
 	NewKnownSize int64 `json:"new_known_size"`
 }
 
-func processPackages(newPackages, oldPackages map[string]commonPackage) (ret []diffPackage) {
+// Function sizes are the sum of code and pclntab, Name is qualified by Package.
+type Function struct {
+	Base
+	Package     string `json:"package"`
+	OldCodeSize int64  `json:"old_code_size"`
+	NewCodeSize int64  `json:"new_code_size"`
+	OldPclnSize int64  `json:"old_pcln_size"`
+	NewPclnSize int64  `json:"new_pcln_size"`
+}
+
+func (f Function) CodeDiff() int64 {
+	return f.NewCodeSize - f.OldCodeSize
+}
+
+func (f Function) PclnDiff() int64 {
+	return f.NewPclnSize - f.OldPclnSize
+}
+
+func functionFullName(pkg, key string) string {
+	if pkg == "" {
+		return key
+	}
+	return pkg + "." + key
+}
+
+func processFunctions(newFunctions, oldFunctions map[string]map[string]commonFunction) (ret []Function) {
+	for pkg, fns := range newFunctions {
+		oldFns := oldFunctions[pkg]
+		for k, v := range fns {
+			d := Function{
+				Package:     pkg,
+				NewCodeSize: v.CodeSize,
+				NewPclnSize: int64(v.PclnSize.Size()),
+			}
+
+			typ := changeTypeAdd
+			if oldV, ok := oldFns[k]; ok {
+				d.OldCodeSize = oldV.CodeSize
+				d.OldPclnSize = int64(oldV.PclnSize.Size())
+				if d.OldCodeSize == d.NewCodeSize && d.OldPclnSize == d.NewPclnSize {
+					continue
+				}
+				typ = changeTypeChange
+			}
+
+			d.Base = Base{
+				Name:       functionFullName(pkg, k),
+				From:       d.OldCodeSize + d.OldPclnSize,
+				To:         d.NewCodeSize + d.NewPclnSize,
+				ChangeType: typ,
+			}
+			ret = append(ret, d)
+		}
+	}
+
+	for pkg, fns := range oldFunctions {
+		newFns := newFunctions[pkg]
+		for k, v := range fns {
+			if _, ok := newFns[k]; ok {
+				continue
+			}
+			d := Function{
+				Package:     pkg,
+				OldCodeSize: v.CodeSize,
+				OldPclnSize: int64(v.PclnSize.Size()),
+			}
+			d.Base = Base{
+				Name:       functionFullName(pkg, k),
+				From:       d.OldCodeSize + d.OldPclnSize,
+				To:         0,
+				ChangeType: changeTypeRemove,
+			}
+			ret = append(ret, d)
+		}
+	}
+
+	return ret
+}
+
+type SymbolGroup string
+
+// symbol groups follow the naming conventions used when attributing symbols
+const (
+	symbolGroupType    SymbolGroup = "type"
+	symbolGroupItab    SymbolGroup = "itab"
+	symbolGroupPclntab SymbolGroup = "pclntab"
+	symbolGroupEmbed   SymbolGroup = "embed"
+	symbolGroupOther   SymbolGroup = "other"
+)
+
+var symbolGroups = []SymbolGroup{
+	symbolGroupType,
+	symbolGroupItab,
+	symbolGroupPclntab,
+	symbolGroupEmbed,
+	symbolGroupOther,
+}
+
+func symbolGroupOf(name string) SymbolGroup {
+	switch {
+	case strings.HasPrefix(name, "type:"):
+		return symbolGroupType
+	case strings.HasPrefix(name, "go:itab."):
+		return symbolGroupItab
+	case strings.HasPrefix(name, "pclntab:"):
+		return symbolGroupPclntab
+	case strings.Contains(name, ".embed:"):
+		// the name, data and hash of a file in an embed.FS variable,
+		// named <var>.embed:<file>.<part> by the DWARF pass
+		return symbolGroupEmbed
+	default:
+		return symbolGroupOther
+	}
+}
+
+type Symbol struct {
+	Base
+	Package string      `json:"package"`
+	Group   SymbolGroup `json:"group"`
+}
+
+func processSymbols(newSymbols, oldSymbols map[string]packageSymbol) (ret []Symbol) {
+	for k, v := range newSymbols {
+		typ := changeTypeAdd
+		fromSize := int64(0)
+		if oldV, ok := oldSymbols[k]; ok {
+			if v.Size == oldV.Size {
+				continue
+			}
+			typ = changeTypeChange
+			fromSize = oldV.Size
+		}
+		ret = append(ret, Symbol{
+			Base:    Base{Name: k, From: fromSize, To: v.Size, ChangeType: typ},
+			Package: v.Package,
+			Group:   symbolGroupOf(k),
+		})
+	}
+
+	for k, v := range oldSymbols {
+		if _, ok := newSymbols[k]; !ok {
+			ret = append(ret, Symbol{
+				Base:    Base{Name: k, From: v.Size, To: 0, ChangeType: changeTypeRemove},
+				Package: v.Package,
+				Group:   symbolGroupOf(k),
+			})
+		}
+	}
+
+	return ret
+}
+
+func processPackages(newPackages, oldPackages map[string]commonPackage) (ret []Package) {
 	for k, v := range newPackages {
 		typ := changeTypeAdd
 		fromSize := int64(0)
@@ -58,15 +218,17 @@ func processPackages(newPackages, oldPackages map[string]commonPackage) (ret []d
 			typ = changeTypeChange
 			fromSize = oldV.Size
 		}
-		ret = append(ret, diffPackage{
+		ret = append(ret, Package{
 			Base: Base{Name: k, From: fromSize, To: v.Size, ChangeType: typ},
+			Type: v.Type,
 		})
 	}
 
 	for k, v := range oldPackages {
 		if _, ok := newPackages[k]; !ok {
-			ret = append(ret, diffPackage{
+			ret = append(ret, Package{
 				Base: Base{Name: k, From: v.Size, To: 0, ChangeType: changeTypeRemove},
+				Type: v.Type,
 			})
 		}
 	}
@@ -74,7 +236,7 @@ func processPackages(newPackages, oldPackages map[string]commonPackage) (ret []d
 	return ret
 }
 
-func processSections(newSections, oldSections []commonSection) (ret []diffSection) {
+func processSections(newSections, oldSections []commonSection) (ret []Section) {
 	newSectionsMap := make(map[string]commonSection)
 	oldSectionsMap := make(map[string]commonSection)
 
@@ -99,7 +261,7 @@ func processSections(newSections, oldSections []commonSection) (ret []diffSectio
 			fromKnownSize = oldV.KnownSize
 		}
 
-		ret = append(ret, diffSection{
+		ret = append(ret, Section{
 			Base:         Base{Name: k, From: fromSize, To: v.UnknownSize(), ChangeType: typ},
 			OldFileSize:  fromFileSize,
 			OldKnownSize: fromKnownSize,
@@ -110,7 +272,7 @@ func processSections(newSections, oldSections []commonSection) (ret []diffSectio
 
 	for k, v := range oldSectionsMap {
 		if _, ok := newSectionsMap[k]; !ok {
-			ret = append(ret, diffSection{
+			ret = append(ret, Section{
 				Base:         Base{Name: k, From: v.UnknownSize(), To: 0, ChangeType: changeTypeRemove},
 				OldFileSize:  v.FileSize,
 				OldKnownSize: v.KnownSize,
@@ -121,8 +283,8 @@ func processSections(newSections, oldSections []commonSection) (ret []diffSectio
 	return ret
 }
 
-func newDiffResult(newResult, oldResult *commonResult) diffResult {
-	ret := diffResult{
+func newDiffResult(newResult, oldResult *commonResult) Result {
+	ret := Result{
 		OldName: oldResult.Name,
 		NewName: newResult.Name,
 		OldSize: oldResult.Size,
@@ -132,12 +294,33 @@ func newDiffResult(newResult, oldResult *commonResult) diffResult {
 		Sections: processSections(newResult.Sections, oldResult.Sections),
 	}
 
-	slices.SortFunc(ret.Packages, func(a, b diffPackage) int {
+	ret.Symbols = processSymbols(newResult.dataSymbols(), oldResult.dataSymbols())
+
+	if newResult.hasFunctionDetail() && oldResult.hasFunctionDetail() {
+		ret.Functions = processFunctions(newResult.functions(), oldResult.functions())
+	}
+
+	slices.SortFunc(ret.Packages, func(a, b Package) int {
 		return diffBaseCmp(a.Base, b.Base)
 	})
-	slices.SortFunc(ret.Sections, func(a, b diffSection) int {
+	slices.SortFunc(ret.Sections, func(a, b Section) int {
 		return diffBaseCmp(a.Base, b.Base)
 	})
+	slices.SortFunc(ret.Functions, func(a, b Function) int {
+		return cmp.Or(diffBaseCmp(a.Base, b.Base), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(ret.Symbols, func(a, b Symbol) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(symbolGroups, a.Group), slices.Index(symbolGroups, b.Group)),
+			diffBaseCmp(a.Base, b.Base),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
 
 	return ret
 }
+
+// Compare diffs two analysis results without printing.
+func Compare(oldResult, newResult *result.Result) Result {
+	return newDiffResult(fromResult(newResult), fromResult(oldResult))
+}
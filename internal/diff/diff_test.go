@@ -4,22 +4,24 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
 )
 
 func TestProcessPackages(t *testing.T) {
 	oldPackages := map[string]commonPackage{
-		"pkg1": {Size: 100},
-		"pkg2": {Size: 200},
+		"pkg1": {Size: 100, Type: entity.PackageTypeMain},
+		"pkg2": {Size: 200, Type: entity.PackageTypeStd},
 	}
 	newPackages := map[string]commonPackage{
-		"pkg1": {Size: 150},
-		"pkg3": {Size: 300},
+		"pkg1": {Size: 150, Type: entity.PackageTypeMain},
+		"pkg3": {Size: 300, Type: entity.PackageTypeVendor},
 	}
 
-	expected := []diffPackage{
-		{Base{Name: "pkg1", From: 100, To: 150, ChangeType: changeTypeChange}},
-		{Base{Name: "pkg3", From: 0, To: 300, ChangeType: changeTypeAdd}},
-		{Base{Name: "pkg2", From: 200, To: 0, ChangeType: changeTypeRemove}},
+	expected := []Package{
+		{Base: Base{Name: "pkg1", From: 100, To: 150, ChangeType: changeTypeChange}, Type: entity.PackageTypeMain},
+		{Base: Base{Name: "pkg3", From: 0, To: 300, ChangeType: changeTypeAdd}, Type: entity.PackageTypeVendor},
+		{Base: Base{Name: "pkg2", From: 200, To: 0, ChangeType: changeTypeRemove}, Type: entity.PackageTypeStd},
 	}
 
 	result := processPackages(newPackages, oldPackages)
@@ -36,7 +38,7 @@ func TestProcessSections(t *testing.T) {
 		{Name: "sec3", FileSize: 300, KnownSize: 150},
 	}
 
-	expected := []diffSection{
+	expected := []Section{
 		{Base: Base{Name: "sec1", From: 50, To: 75, ChangeType: changeTypeChange}, OldFileSize: 100, OldKnownSize: 50, NewFileSize: 150, NewKnownSize: 75},
 		{Base: Base{Name: "sec3", From: 0, To: 150, ChangeType: changeTypeAdd}, OldFileSize: 0, OldKnownSize: 0, NewFileSize: 300, NewKnownSize: 150},
 		{Base: Base{Name: "sec2", From: 100, To: 0, ChangeType: changeTypeRemove}, OldFileSize: 200, OldKnownSize: 100, NewFileSize: 0, NewKnownSize: 0},
@@ -68,13 +70,13 @@ func TestNewDiffResult(t *testing.T) {
 		},
 	}
 
-	expected := diffResult{
-		Packages: []diffPackage{
-			{Base{Name: "pkg3", From: 0, To: 300, ChangeType: changeTypeAdd}},
-			{Base{Name: "pkg1", From: 100, To: 150, ChangeType: changeTypeChange}},
-			{Base{Name: "pkg2", From: 200, To: 0, ChangeType: changeTypeRemove}},
+	expected := Result{
+		Packages: []Package{
+			{Base: Base{Name: "pkg3", From: 0, To: 300, ChangeType: changeTypeAdd}},
+			{Base: Base{Name: "pkg1", From: 100, To: 150, ChangeType: changeTypeChange}},
+			{Base: Base{Name: "pkg2", From: 200, To: 0, ChangeType: changeTypeRemove}},
 		},
-		Sections: []diffSection{
+		Sections: []Section{
 			{Base: Base{Name: "sec3", From: 0, To: 150, ChangeType: changeTypeAdd}, OldFileSize: 0, OldKnownSize: 0, NewFileSize: 300, NewKnownSize: 150},
 			{Base: Base{Name: "sec1", From: 50, To: 75, ChangeType: changeTypeChange}, OldFileSize: 100, OldKnownSize: 50, NewFileSize: 150, NewKnownSize: 75},
 			{Base: Base{Name: "sec2", From: 100, To: 0, ChangeType: changeTypeRemove}, OldFileSize: 200, OldKnownSize: 100, NewFileSize: 0, NewKnownSize: 0},
@@ -84,3 +86,118 @@ func TestNewDiffResult(t *testing.T) {
 	result := newDiffResult(newResult, oldResult)
 	assert.Equal(t, expected, result)
 }
+
+func TestProcessFunctions(t *testing.T) {
+	oldFunctions := map[string]map[string]commonFunction{
+		"pkg1": {
+			"F":    {Name: "F", CodeSize: 100, PclnSize: entity.PclnSymbolSize{Header: 10}},
+			"Same": {Name: "Same", CodeSize: 100},
+			"Gone": {Name: "Gone", CodeSize: 50},
+		},
+		"pkg2": {
+			"G": {Name: "G", CodeSize: 20},
+		},
+	}
+	newFunctions := map[string]map[string]commonFunction{
+		"pkg1": {
+			"F":    {Name: "F", CodeSize: 150, PclnSize: entity.PclnSymbolSize{Header: 12}},
+			"Same": {Name: "Same", CodeSize: 100},
+			"*T.M": {Name: "M", Receiver: "*T", CodeSize: 30},
+		},
+	}
+
+	expected := []Function{
+		{
+			Base:        Base{Name: "pkg1.F", From: 110, To: 162, ChangeType: changeTypeChange},
+			Package:     "pkg1",
+			OldCodeSize: 100, NewCodeSize: 150,
+			OldPclnSize: 10, NewPclnSize: 12,
+		},
+		{
+			Base:        Base{Name: "pkg1.*T.M", From: 0, To: 30, ChangeType: changeTypeAdd},
+			Package:     "pkg1",
+			NewCodeSize: 30,
+		},
+		{
+			Base:        Base{Name: "pkg1.Gone", From: 50, To: 0, ChangeType: changeTypeRemove},
+			Package:     "pkg1",
+			OldCodeSize: 50,
+		},
+		{
+			Base:        Base{Name: "pkg2.G", From: 20, To: 0, ChangeType: changeTypeRemove},
+			Package:     "pkg2",
+			OldCodeSize: 20,
+		},
+	}
+
+	result := processFunctions(newFunctions, oldFunctions)
+	assert.ElementsMatch(t, expected, result)
+}
+
+func TestNewDiffResultSkipsFunctionsWithoutDetail(t *testing.T) {
+	withDetail := &commonResult{
+		Packages: map[string]commonPackage{
+			"pkg1": {
+				Name:  "pkg1",
+				Size:  100,
+				Files: []commonFile{{FilePath: "a.go", Functions: []commonFunction{{Name: "F", CodeSize: 100}}}},
+			},
+		},
+	}
+	compact := &commonResult{
+		Packages: map[string]commonPackage{
+			"pkg1": {Name: "pkg1", Size: 50, Files: []commonFile{{FilePath: "a.go"}}},
+		},
+	}
+
+	assert.Empty(t, newDiffResult(withDetail, compact).Functions)
+
+	result := newDiffResult(withDetail, &commonResult{
+		Packages: map[string]commonPackage{
+			"pkg1": {
+				Name:  "pkg1",
+				Size:  50,
+				Files: []commonFile{{FilePath: "a.go", Functions: []commonFunction{{Name: "F", CodeSize: 50}}}},
+			},
+		},
+	})
+	assert.Equal(t, []Function{{
+		Base:        Base{Name: "pkg1.F", From: 50, To: 100, ChangeType: changeTypeChange},
+		Package:     "pkg1",
+		OldCodeSize: 50,
+		NewCodeSize: 100,
+	}}, result.Functions)
+}
+
+func TestSymbolGroupOf(t *testing.T) {
+	assert.Equal(t, symbolGroupType, symbolGroupOf("type:*main.T"))
+	assert.Equal(t, symbolGroupItab, symbolGroupOf("go:itab.*os.File,io.Writer"))
+	assert.Equal(t, symbolGroupPclntab, symbolGroupOf("pclntab:ftab[main]"))
+	assert.Equal(t, symbolGroupEmbed, symbolGroupOf("main.assets.embed:static/index.html.data"))
+	assert.Equal(t, symbolGroupEmbed, symbolGroupOf("main.assets.embed:static/index.html.name"))
+	// the embed.FS header itself
+	assert.Equal(t, symbolGroupOther, symbolGroupOf("main.assets"))
+	assert.Equal(t, symbolGroupOther, symbolGroupOf("main.table"))
+}
+
+func TestProcessSymbols(t *testing.T) {
+	oldSymbols := map[string]packageSymbol{
+		"type:*main.T": {Package: "runtime/generated", Size: 100},
+		"main.assets.embed:static/index.html.data": {Package: "main", Size: 40},
+		"main.same": {Package: "main", Size: 8},
+	}
+	newSymbols := map[string]packageSymbol{
+		"type:*main.T":               {Package: "runtime/generated", Size: 120},
+		"go:itab.*os.File,io.Writer": {Package: "runtime/itabs", Size: 32},
+		"main.same":                  {Package: "main", Size: 8},
+	}
+
+	expected := []Symbol{
+		{Base: Base{Name: "type:*main.T", From: 100, To: 120, ChangeType: changeTypeChange}, Package: "runtime/generated", Group: symbolGroupType},
+		{Base: Base{Name: "go:itab.*os.File,io.Writer", From: 0, To: 32, ChangeType: changeTypeAdd}, Package: "runtime/itabs", Group: symbolGroupItab},
+		{Base: Base{Name: "main.assets.embed:static/index.html.data", From: 40, To: 0, ChangeType: changeTypeRemove}, Package: "main", Group: symbolGroupEmbed},
+	}
+
+	result := processSymbols(newSymbols, oldSymbols)
+	assert.ElementsMatch(t, expected, result)
+}
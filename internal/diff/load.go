@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -9,6 +10,7 @@ import (
 	"encoding/json/v2"
 
 	"github.com/Zxilly/go-size-analyzer/internal"
+	"github.com/Zxilly/go-size-analyzer/internal/budget"
 	"github.com/Zxilly/go-size-analyzer/internal/printer"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
@@ -22,6 +24,9 @@ type Options struct {
 	Format string
 
 	Indent *int
+
+	// Budget is checked against the new target and its growth, nil disables it.
+	Budget *budget.Config
 }
 
 func formatAnalyzer(analyzers []string) string {
@@ -32,13 +37,13 @@ func formatAnalyzer(analyzers []string) string {
 	return strings.Join(analyzers, ", ")
 }
 
-func Diff(writer io.Writer, options Options) error {
-	oldResult, err := autoLoadFile(options.OldTarget, options.Options)
+func Diff(ctx context.Context, writer io.Writer, options Options) error {
+	oldResult, err := autoLoadFile(ctx, options.OldTarget, options.Options)
 	if err != nil {
 		return err
 	}
 
-	newResult, err := autoLoadFile(options.NewTarget, options.Options)
+	newResult, err := autoLoadFile(ctx, options.NewTarget, options.Options)
 	if err != nil {
 		return err
 	}
@@ -51,19 +56,35 @@ func Diff(writer io.Writer, options Options) error {
 
 	diff := newDiffResult(newResult, oldResult)
 
+	if !oldResult.hasFunctionDetail() || !newResult.hasFunctionDetail() {
+		slog.Warn("Function diff skipped, at least one side has no function detail, e.g. a compact JSON result")
+	}
+
 	switch options.Format {
 	case printer.FormatJSON:
-		return printer.JSON(&diff, writer, &printer.JSONOption{
+		err = printer.JSON(&diff, writer, &printer.JSONOption{
 			Indent: nil,
 		})
 	case printer.FormatText:
-		return text(&diff, writer)
+		err = text(&diff, writer)
+	case printer.FormatMarkdown:
+		err = markdown(&diff, writer)
 	default:
-		return fmt.Errorf("format %s is not supported in diff mode", options.Format)
+		err = fmt.Errorf("format %s is not supported in diff mode", options.Format)
+	}
+	if err != nil || options.Budget == nil {
+		return err
 	}
+
+	return budget.Report(utils.SyncStderr, checkBudget(options.Budget, oldResult, newResult))
+}
+
+func checkBudget(c *budget.Config, oldResult, newResult *commonResult) []budget.Violation {
+	oldSizes, newSizes := oldResult.budgetSizes(), newResult.budgetSizes()
+	return append(c.Check(newSizes), c.CheckGrowth(oldSizes, newSizes)...)
 }
 
-func autoLoadFile(name string, options internal.Options) (*commonResult, error) {
+func autoLoadFile(ctx context.Context, name string, options internal.Options) (*commonResult, error) {
 	reader, err := utils.OpenBinary(name)
 	if err != nil {
 		return nil, fmt.Errorf("open %s: %w", name, err)
@@ -84,7 +105,7 @@ func autoLoadFile(name string, options internal.Options) (*commonResult, error)
 		return r, nil
 	}
 
-	fullResult, err := internal.Analyze(
+	fullResult, err := internal.AnalyzeContext(ctx,
 		name,
 		reader,
 		uint64(reader.Len()),
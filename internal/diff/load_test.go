@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -36,7 +37,7 @@ func TestDiffJSONAndBinary(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			require.NoError(t, Diff(io.Discard, Options{
+			require.NoError(t, Diff(context.Background(), io.Discard, Options{
 				OldTarget: tt.old,
 				NewTarget: tt.new,
 				Format:    tt.format,
@@ -67,7 +68,7 @@ func TestDifferentAnalyzer(t *testing.T) {
 	createFile(first, []entity.Analyzer{entity.AnalyzerDwarf, entity.AddrSourceSymbol})
 	createFile(second, []entity.Analyzer{entity.AnalyzerDisasm})
 
-	require.Error(t, Diff(io.Discard, Options{
+	require.Error(t, Diff(context.Background(), io.Discard, Options{
 		OldTarget: first,
 		NewTarget: second,
 	}))
@@ -1,13 +1,18 @@
 package diff
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/jedib0t/go-pretty/v6/table"
 
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/printer"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
 
@@ -52,7 +57,7 @@ func diffDisplayName(name string) string {
 	return name
 }
 
-func text(r *diffResult, writer io.Writer) error {
+func text(r *Result, writer io.Writer) error {
 	slog.Info("Printing text diff report")
 
 	t := table.NewWriter()
@@ -97,6 +102,18 @@ func text(r *diffResult, writer io.Writer) error {
 
 	data := []byte(t.Render() + "\n")
 
+	if len(r.Functions) > 0 {
+		ft := functionTable(r, plainName)
+		ft.SetTitle("Function diff between %s and %s", r.OldName, r.NewName)
+		data = append(data, ft.Render()+"\n"...)
+	}
+
+	if len(r.Symbols) > 0 {
+		st := symbolTable(r, plainName)
+		st.SetTitle("Data symbol diff between %s and %s", r.OldName, r.NewName)
+		data = append(data, st.Render()+"\n"...)
+	}
+
 	slog.Info("Diff report rendered")
 
 	_, err := writer.Write(data)
@@ -105,3 +122,211 @@ func text(r *diffResult, writer io.Writer) error {
 
 	return err
 }
+
+// plainName leaves names as is in the text report
+func plainName(name string) string {
+	return name
+}
+
+func functionTable(r *Result, name func(string) string) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	t.AppendHeader(table.Row{"Percent", "Name", "Old Size", "New Size", "Diff", "Code Diff", "Pcln Diff"})
+
+	var oldSize, newSize int64
+	for _, fn := range r.Functions {
+		oldSize += fn.From
+		newSize += fn.To
+
+		t.AppendRow(table.Row{
+			diffString(fn.Base),
+			name(fn.Name),
+			bytesWithIgnore(fn.From),
+			bytesWithIgnore(fn.To),
+			signedBytesString(fn.To - fn.From),
+			signedBytesString(fn.CodeDiff()),
+			signedBytesString(fn.PclnDiff()),
+		})
+	}
+
+	t.AppendFooter(table.Row{
+		"",
+		fmt.Sprintf("%d functions", len(r.Functions)),
+		humanize.Bytes(uint64(oldSize)),
+		humanize.Bytes(uint64(newSize)),
+		signedBytesString(newSize - oldSize),
+	})
+
+	return t
+}
+
+// symbolTopN limits the rows per group and change type in the text report
+const symbolTopN = 10
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func symbolTable(r *Result, name func(string) string) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	t.AppendHeader(table.Row{"Group", "Percent", "Name", "Package", "Old Size", "New Size", "Diff"})
+
+	byGroup := make(map[SymbolGroup][]Symbol)
+	for _, sym := range r.Symbols {
+		byGroup[sym.Group] = append(byGroup[sym.Group], sym)
+	}
+
+	first := true
+	for _, group := range symbolGroups {
+		symbols := byGroup[group]
+		if len(symbols) == 0 {
+			continue
+		}
+		if !first {
+			t.AppendSeparator()
+		}
+		first = false
+
+		for _, typ := range []ChangeType{changeTypeAdd, changeTypeRemove, changeTypeChange} {
+			var picked []Symbol
+			for _, sym := range symbols {
+				if sym.ChangeType == typ {
+					picked = append(picked, sym)
+				}
+			}
+			slices.SortStableFunc(picked, func(a, b Symbol) int {
+				return -cmp.Compare(absInt64(a.To-a.From), absInt64(b.To-b.From))
+			})
+
+			for _, sym := range picked[:min(len(picked), symbolTopN)] {
+				t.AppendRow(table.Row{
+					group,
+					diffString(sym.Base),
+					name(sym.Name),
+					name(diffDisplayName(sym.Package)),
+					bytesWithIgnore(sym.From),
+					bytesWithIgnore(sym.To),
+					signedBytesString(sym.To - sym.From),
+				})
+			}
+			if len(picked) > symbolTopN {
+				t.AppendRow(table.Row{group, "", fmt.Sprintf("... %d more %s", len(picked)-symbolTopN, typ)})
+			}
+		}
+	}
+
+	return t
+}
+
+// markdownPackageTypes is the order of the collapsible package sections in the markdown report
+var markdownPackageTypes = []entity.PackageType{
+	entity.PackageTypeMain,
+	entity.PackageTypeStd,
+	entity.PackageTypeVendor,
+	entity.PackageTypeGenerated,
+	entity.PackageTypeCGO,
+	entity.PackageTypeUnknown,
+}
+
+func markdownDiffSummary(title string, from, to int64, count int, unit string) string {
+	return fmt.Sprintf("<b>%s</b>: %s, %d %s", title, signedBytesString(to-from), count, unit)
+}
+
+func markdown(r *Result, writer io.Writer) error {
+	slog.Info("Printing markdown diff report")
+
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "## Diff between %s and %s\n\n", printer.MarkdownCode(r.OldName), printer.MarkdownCode(r.NewName))
+	_, _ = fmt.Fprintf(sb, "- **Old Size:** %s\n", humanize.Bytes(uint64(r.OldSize)))
+	_, _ = fmt.Fprintf(sb, "- **New Size:** %s\n", humanize.Bytes(uint64(r.NewSize)))
+	_, _ = fmt.Fprintf(sb, "- **Diff:** %s (%s)\n\n", signedBytesString(r.NewSize-r.OldSize), diffString(Base{
+		From:       r.OldSize,
+		To:         r.NewSize,
+		ChangeType: changeTypeChange,
+	}))
+
+	for _, typ := range markdownPackageTypes {
+		t := table.NewWriter()
+		t.SetStyle(utils.GetTableStyle())
+		t.AppendHeader(table.Row{"Percent", "Name", "Old Size", "New Size", "Diff"})
+
+		var count int
+		var from, to int64
+		for _, pkg := range r.Packages {
+			pkgType := pkg.Type
+			if pkgType == "" {
+				// missing in results written by older versions
+				pkgType = entity.PackageTypeUnknown
+			}
+			if pkgType != typ {
+				continue
+			}
+			count++
+			from += pkg.From
+			to += pkg.To
+			t.AppendRow(table.Row{
+				diffString(pkg.Base),
+				printer.MarkdownCode(diffDisplayName(pkg.Name)),
+				bytesWithIgnore(pkg.From),
+				bytesWithIgnore(pkg.To),
+				signedBytesString(pkg.To - pkg.From),
+			})
+		}
+		if count == 0 {
+			continue
+		}
+		printer.MarkdownDetails(sb, markdownDiffSummary(typ, from, to, count, "packages"), t)
+	}
+
+	if len(r.Sections) > 0 {
+		t := table.NewWriter()
+		t.SetStyle(utils.GetTableStyle())
+		t.AppendHeader(table.Row{"Percent", "Name", "Old Size", "New Size", "Diff"})
+
+		var from, to int64
+		for _, section := range r.Sections {
+			from += section.From
+			to += section.To
+			t.AppendRow(table.Row{
+				diffString(section.Base),
+				printer.MarkdownCode(section.Name),
+				bytesWithIgnore(section.From),
+				bytesWithIgnore(section.To),
+				signedBytesString(section.To - section.From),
+			})
+		}
+		printer.MarkdownDetails(sb, markdownDiffSummary("sections", from, to, len(r.Sections), "sections"), t)
+	}
+
+	if len(r.Functions) > 0 {
+		var from, to int64
+		for _, fn := range r.Functions {
+			from += fn.From
+			to += fn.To
+		}
+		printer.MarkdownDetails(sb, markdownDiffSummary("functions", from, to, len(r.Functions), "functions"), functionTable(r, printer.MarkdownCode))
+	}
+
+	if len(r.Symbols) > 0 {
+		var from, to int64
+		for _, sym := range r.Symbols {
+			from += sym.From
+			to += sym.To
+		}
+		printer.MarkdownDetails(sb, markdownDiffSummary("data symbols", from, to, len(r.Symbols), "symbols"), symbolTable(r, printer.MarkdownCode))
+	}
+
+	slog.Info("Diff report rendered")
+
+	_, err := writer.Write([]byte(sb.String()))
+
+	slog.Info("Diff report written")
+
+	return err
+}
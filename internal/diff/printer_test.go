@@ -2,10 +2,13 @@ package diff
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
 )
 
 func TestDiffStringChangeTypeChangeReturnsPercentage(t *testing.T) {
@@ -40,15 +43,15 @@ func TestDiffDisplayNameFallsBackForEmptyPackageName(t *testing.T) {
 
 func TestTextRendersTableCorrectly(t *testing.T) {
 	var buf bytes.Buffer
-	r := &diffResult{
+	r := &Result{
 		OldName: "old",
 		NewName: "new",
 		OldSize: 100,
 		NewSize: 150,
-		Sections: []diffSection{
+		Sections: []Section{
 			{Base: Base{Name: "sec1", From: 100, To: 150, ChangeType: changeTypeChange}},
 		},
-		Packages: []diffPackage{
+		Packages: []Package{
 			{Base: Base{Name: "pkg1", From: 100, To: 150, ChangeType: changeTypeChange}},
 		},
 	}
@@ -61,8 +64,92 @@ func TestTextRendersTableCorrectly(t *testing.T) {
 
 func TestTextHandlesEmptyResultWithoutError(t *testing.T) {
 	var buf bytes.Buffer
-	r := &diffResult{}
+	r := &Result{}
 	err := text(r, &buf)
 	require.NoError(t, err)
 	assert.Contains(t, buf.String(), "Diff between  and ")
 }
+
+func TestTextRendersFunctionTable(t *testing.T) {
+	var buf bytes.Buffer
+	r := &Result{
+		OldName: "old",
+		NewName: "new",
+		Functions: []Function{
+			{
+				Base:        Base{Name: "pkg1.F", From: 110, To: 162, ChangeType: changeTypeChange},
+				Package:     "pkg1",
+				OldCodeSize: 100, NewCodeSize: 150,
+				OldPclnSize: 10, NewPclnSize: 12,
+			},
+		},
+	}
+	err := text(r, &buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Function diff between old and new")
+	assert.Contains(t, buf.String(), "pkg1.F")
+	assert.Contains(t, buf.String(), "+50 B")
+}
+
+func TestTextRendersSymbolTable(t *testing.T) {
+	var buf bytes.Buffer
+	r := &Result{
+		OldName: "old",
+		NewName: "new",
+		Symbols: []Symbol{
+			{Base: Base{Name: "type:*main.T", From: 100, To: 120, ChangeType: changeTypeChange}, Package: "runtime/generated", Group: symbolGroupType},
+		},
+	}
+	for i := range symbolTopN + 2 {
+		r.Symbols = append(r.Symbols, Symbol{
+			Base:  Base{Name: fmt.Sprintf("go:itab.%d", i), To: int64(i + 1), ChangeType: changeTypeAdd},
+			Group: symbolGroupItab,
+		})
+	}
+
+	err := text(r, &buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Data symbol diff between old and new")
+	assert.Contains(t, buf.String(), "type:*main.T")
+	assert.Contains(t, buf.String(), "go:itab.11")
+	assert.NotContains(t, buf.String(), "go:itab.0 ")
+	assert.Contains(t, buf.String(), "... 2 more add")
+}
+
+func TestMarkdownRendersDetailsPerPackageType(t *testing.T) {
+	var buf bytes.Buffer
+	r := &Result{
+		OldName: "old",
+		NewName: "new",
+		OldSize: 300,
+		NewSize: 450,
+		Packages: []Package{
+			{Base: Base{Name: "main", From: 100, To: 150, ChangeType: changeTypeChange}, Type: entity.PackageTypeMain},
+			{Base: Base{Name: "runtime", From: 100, To: 200, ChangeType: changeTypeChange}, Type: entity.PackageTypeStd},
+			{Base: Base{Name: "legacy", From: 100, To: 100, ChangeType: changeTypeChange}},
+		},
+		Sections: []Section{
+			{Base: Base{Name: ".rodata", From: 10, To: 20, ChangeType: changeTypeChange}},
+		},
+		Functions: []Function{
+			{Base: Base{Name: "main.*T.M", From: 10, To: 20, ChangeType: changeTypeChange}, Package: "main"},
+		},
+		Symbols: []Symbol{
+			{Base: Base{Name: "type:*T", From: 1, To: 5, ChangeType: changeTypeChange}, Group: symbolGroupType},
+		},
+	}
+	require.NoError(t, markdown(r, &buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "## Diff between `old` and `new`")
+	assert.Contains(t, out, "- **Diff:** +150 B (+50.00%)")
+	assert.Contains(t, out, "<summary><b>main</b>: +50 B, 1 packages</summary>")
+	assert.Contains(t, out, "<summary><b>std</b>: +100 B, 1 packages</summary>")
+	assert.Contains(t, out, "<summary><b>unknown</b>: +0 B, 1 packages</summary>")
+	assert.Contains(t, out, "<summary><b>sections</b>: +10 B, 1 sections</summary>")
+	assert.Contains(t, out, "<summary><b>functions</b>: +10 B, 1 functions</summary>")
+	assert.Contains(t, out, "| `runtime` |")
+	assert.Contains(t, out, "| `main.*T.M` |")
+	assert.Contains(t, out, "| `type:*T` | `<autogenerated>` |")
+	assert.NotContains(t, out, "┌")
+}
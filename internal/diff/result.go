@@ -1,6 +1,9 @@
 package diff
 
 import (
+	"maps"
+
+	"github.com/Zxilly/go-size-analyzer/internal/budget"
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
 	"github.com/Zxilly/go-size-analyzer/internal/result"
 )
@@ -16,8 +19,154 @@ type commonResult struct {
 }
 
 type commonPackage struct {
-	Name string `json:"name"`
-	Size int64  `json:"size"`
+	Name string             `json:"name"`
+	Type entity.PackageType `json:"type"`
+	Size int64              `json:"size"`
+
+	SubPackages map[string]commonPackage `json:"subPackages"`
+	Files       []commonFile             `json:"files"`
+	Symbols     []commonSymbol           `json:"symbols"`
+}
+
+type commonSymbol struct {
+	Name string          `json:"name"`
+	Size int64           `json:"size"`
+	Type entity.AddrType `json:"type"`
+}
+
+type packageSymbol struct {
+	Package string
+	Size    int64
+}
+
+// commonFile only decodes functions, a compact result JSON carries none.
+type commonFile struct {
+	FilePath  string           `json:"file_path"`
+	Functions []commonFunction `json:"functions"`
+}
+
+type commonFunction struct {
+	Name     string `json:"name"`
+	CodeSize int64  `json:"code_size"`
+	Receiver string `json:"receiver"`
+
+	PclnSize entity.PclnSymbolSize `json:"pcln_size"`
+
+	// Wrappers are the functions folded into it, diffed as functions of their own.
+	Wrappers []commonFunction `json:"wrappers,omitempty"`
+}
+
+// Key identifies a function within its package, methods are qualified by receiver.
+func (f commonFunction) Key() string {
+	if f.Receiver == "" {
+		return f.Name
+	}
+	return f.Receiver + "." + f.Name
+}
+
+// hasFunctionDetail reports whether any function is known,
+// which is false for results loaded from a compact JSON.
+func (c *commonResult) hasFunctionDetail() bool {
+	var walk func(pkgs map[string]commonPackage) bool
+	walk = func(pkgs map[string]commonPackage) bool {
+		for _, p := range pkgs {
+			for _, f := range p.Files {
+				if len(f.Functions) > 0 {
+					return true
+				}
+			}
+			if walk(p.SubPackages) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(c.Packages)
+}
+
+// functions groups function sizes by the full package name and function key.
+func (c *commonResult) functions() map[string]map[string]commonFunction {
+	ret := make(map[string]map[string]commonFunction)
+
+	// the wrappers are unfolded, so folded and unfolded results match
+	var add func(fns map[string]commonFunction, fn commonFunction)
+	add = func(fns map[string]commonFunction, fn commonFunction) {
+		for _, w := range fn.Wrappers {
+			add(fns, w)
+		}
+		fn.Wrappers = nil
+
+		key := fn.Key()
+		if old, ok := fns[key]; ok {
+			// same name can appear more than once, e.g. in different files
+			fn.CodeSize += old.CodeSize
+			fn.PclnSize = mergePclnSize(old.PclnSize, fn.PclnSize)
+		}
+		fns[key] = fn
+	}
+
+	var walk func(pkgs map[string]commonPackage)
+	walk = func(pkgs map[string]commonPackage) {
+		for _, p := range pkgs {
+			for _, f := range p.Files {
+				for _, fn := range f.Functions {
+					fns, ok := ret[p.Name]
+					if !ok {
+						fns = make(map[string]commonFunction)
+						ret[p.Name] = fns
+					}
+					add(fns, fn)
+				}
+			}
+			walk(p.SubPackages)
+		}
+	}
+	walk(c.Packages)
+
+	return ret
+}
+
+// dataSymbols sums data symbol sizes by name, remembering the owning package.
+func (c *commonResult) dataSymbols() map[string]packageSymbol {
+	ret := make(map[string]packageSymbol)
+
+	var walk func(pkgs map[string]commonPackage)
+	walk = func(pkgs map[string]commonPackage) {
+		for _, p := range pkgs {
+			for _, sym := range p.Symbols {
+				if sym.Type != entity.AddrTypeData {
+					continue
+				}
+				old, ok := ret[sym.Name]
+				if !ok {
+					old.Package = p.Name
+				}
+				old.Size += sym.Size
+				ret[sym.Name] = old
+			}
+			walk(p.SubPackages)
+		}
+	}
+	walk(c.Packages)
+
+	return ret
+}
+
+func mergePclnSize(a, b entity.PclnSymbolSize) entity.PclnSymbolSize {
+	ret := entity.PclnSymbolSize{
+		Name:     a.Name + b.Name,
+		PCFile:   a.PCFile + b.PCFile,
+		PCSP:     a.PCSP + b.PCSP,
+		PCLN:     a.PCLN + b.PCLN,
+		Header:   a.Header + b.Header,
+		FuncData: a.FuncData + b.FuncData,
+		PCData:   make(map[string]int, len(a.PCData)),
+	}
+	maps.Copy(ret.PCData, a.PCData)
+	for k, v := range b.PCData {
+		ret.PCData[k] += v
+	}
+	return ret
 }
 
 type commonSection struct {
@@ -31,6 +180,30 @@ func (c commonSection) UnknownSize() int64 {
 	return c.FileSize - c.KnownSize
 }
 
+func (c *commonResult) budgetSizes() budget.Sizes {
+	s := budget.Sizes{
+		Name:     c.Name,
+		Size:     c.Size,
+		Packages: make(map[string]int64),
+		Sections: make(map[string]int64, len(c.Sections)),
+	}
+
+	var walk func(pkgs map[string]commonPackage)
+	walk = func(pkgs map[string]commonPackage) {
+		for _, p := range pkgs {
+			s.Packages[p.Name] = p.Size
+			walk(p.SubPackages)
+		}
+	}
+	walk(c.Packages)
+
+	for _, sect := range c.Sections {
+		s.Sections[sect.Name] = sect.FileSize
+	}
+
+	return s
+}
+
 func fromResult(r *result.Result) *commonResult {
 	c := commonResult{
 		Name:      r.Name,
@@ -41,10 +214,7 @@ func fromResult(r *result.Result) *commonResult {
 	}
 
 	for k, v := range r.Packages {
-		c.Packages[k] = commonPackage{
-			Name: v.Name,
-			Size: int64(v.Size),
-		}
+		c.Packages[k] = fromPackage(v)
 	}
 
 	for i, v := range r.Sections {
@@ -57,3 +227,57 @@ func fromResult(r *result.Result) *commonResult {
 
 	return &c
 }
+
+func fromPackage(p *entity.Package) commonPackage {
+	c := commonPackage{
+		Name:        p.Name,
+		Type:        p.Type,
+		Size:        int64(p.Size),
+		SubPackages: make(map[string]commonPackage, len(p.SubPackages)),
+		Files:       make([]commonFile, len(p.Files)),
+		Symbols:     make([]commonSymbol, len(p.Symbols)),
+	}
+
+	for i, sym := range p.Symbols {
+		c.Symbols[i] = commonSymbol{
+			Name: sym.Name,
+			Size: int64(sym.Size),
+			Type: sym.Type,
+		}
+	}
+
+	for k, v := range p.SubPackages {
+		c.SubPackages[k] = fromPackage(v)
+	}
+
+	for i, f := range p.Files {
+		c.Files[i] = commonFile{
+			FilePath:  f.FilePath,
+			Functions: fromFunctions(f.Functions),
+		}
+	}
+
+	return c
+}
+
+func fromFunctions(fns []*entity.Function) []commonFunction {
+	ret := make([]commonFunction, len(fns))
+	for i, fn := range fns {
+		pclnSize := fn.PclnSize
+		if pclnSize.PCData == nil {
+			// keep the same shape as decoded from json
+			pclnSize.PCData = make(map[string]int)
+		}
+
+		ret[i] = commonFunction{
+			Name:     fn.Name,
+			CodeSize: int64(fn.CodeSize),
+			Receiver: fn.Receiver,
+			PclnSize: pclnSize,
+		}
+		if len(fn.Wrappers) > 0 {
+			ret[i].Wrappers = fromFunctions(fn.Wrappers)
+		}
+	}
+	return ret
+}
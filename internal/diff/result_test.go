@@ -8,7 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Zxilly/go-size-analyzer/internal/budget"
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
 	"github.com/Zxilly/go-size-analyzer/internal/printer"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
 	"github.com/Zxilly/go-size-analyzer/internal/test"
 )
 
@@ -58,5 +61,154 @@ func TestCommonResultFromFullAndCompactJSON(t *testing.T) {
 	err = json.UnmarshalRead(fullJSONData, crFromFullJSON)
 	require.NoError(t, err)
 
-	assert.Equal(t, crFromCompactJSON, crFromFullJSON) //nolint:testifylint // workaround github.com/Antonboom/testifylint/issues/198
+	assert.True(t, crFromFullJSON.hasFunctionDetail())
+	assert.False(t, crFromCompactJSON.hasFunctionDetail())
+
+	assert.Equal(t, crFromCompactJSON.Sections, crFromFullJSON.Sections)
+	assert.Len(t, crFromCompactJSON.Packages, len(crFromFullJSON.Packages))
+	for k, v := range crFromFullJSON.Packages {
+		assert.Equal(t, v.Size, crFromCompactJSON.Packages[k].Size)
+	}
+}
+
+func TestCommonResultFunctions(t *testing.T) {
+	fn := func(name, receiver string, codeSize int64, pcln uint64) commonFunction {
+		return commonFunction{
+			Name:     name,
+			Receiver: receiver,
+			CodeSize: codeSize,
+			PclnSize: entity.PclnSymbolSize{Header: pcln},
+		}
+	}
+
+	r := &commonResult{
+		Packages: map[string]commonPackage{
+			"a": {
+				Name: "a",
+				Files: []commonFile{
+					{FilePath: "a.go", Functions: []commonFunction{fn("F", "", 10, 1), fn("M", "*T", 20, 2)}},
+					{FilePath: "b.go", Functions: []commonFunction{fn("F", "", 5, 1)}},
+				},
+				SubPackages: map[string]commonPackage{
+					"b": {
+						Name:  "a/b",
+						Files: []commonFile{{FilePath: "c.go", Functions: []commonFunction{fn("G", "", 30, 3)}}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.True(t, r.hasFunctionDetail())
+
+	fns := r.functions()
+	assert.Equal(t, int64(15), fns["a"]["F"].CodeSize)
+	pclnSize := fns["a"]["F"].PclnSize
+	assert.Equal(t, uint64(2), pclnSize.Size())
+	assert.Equal(t, int64(20), fns["a"]["*T.M"].CodeSize)
+	assert.Equal(t, int64(30), fns["a/b"]["G"].CodeSize)
+}
+
+func TestCommonResultFunctionsUnfoldWrappers(t *testing.T) {
+	fn := func(name string, codeSize uint64, wrappers ...*entity.Function) *entity.Function {
+		return &entity.Function{
+			Name:     name,
+			CodeSize: codeSize,
+			PclnSize: entity.PclnSymbolSize{Header: 1},
+			Wrappers: wrappers,
+		}
+	}
+
+	p := entity.NewPackage()
+	p.Name = "main"
+	p.Files = []*entity.File{{
+		FilePath: "main.go",
+		Functions: []*entity.Function{
+			fn("main", 100, fn("main.func1", 10, fn("main.func1.1", 5)), fn("main.gowrap1", 3)),
+			fn("F", 20),
+		},
+	}}
+	folded := &result.Result{Name: "folded", Analyzers: []entity.Analyzer{}, Packages: entity.PackageMap{"main": p}}
+
+	want := map[string]int64{"main": 100, "main.func1": 10, "main.func1.1": 5, "main.gowrap1": 3, "F": 20}
+	check := func(c *commonResult) {
+		t.Helper()
+		fns := c.functions()["main"]
+		require.Len(t, fns, len(want))
+		for name, size := range want {
+			assert.Equal(t, size, fns[name].CodeSize, name)
+			assert.Empty(t, fns[name].Wrappers, name)
+		}
+	}
+
+	check(fromResult(folded))
+
+	jsonData := new(bytes.Buffer)
+	require.NoError(t, printer.JSON(folded, jsonData, &printer.JSONOption{}))
+	fromJSON := new(commonResult)
+	require.NoError(t, json.UnmarshalRead(jsonData, fromJSON))
+	check(fromJSON)
+	assert.Equal(t, fromResult(folded), fromJSON)
+}
+
+func TestCommonResultDataSymbols(t *testing.T) {
+	r := &commonResult{
+		Packages: map[string]commonPackage{
+			"main": {
+				Name: "main",
+				Symbols: []commonSymbol{
+					{Name: "main.table", Size: 16, Type: entity.AddrTypeData},
+					{Name: "main.main", Size: 64, Type: entity.AddrTypeText},
+				},
+				SubPackages: map[string]commonPackage{
+					"sub": {
+						Name:    "main/sub",
+						Symbols: []commonSymbol{{Name: "main/sub.buf", Size: 32, Type: entity.AddrTypeData}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]packageSymbol{
+		"main.table":   {Package: "main", Size: 16},
+		"main/sub.buf": {Package: "main/sub", Size: 32},
+	}, r.dataSymbols())
+}
+
+func TestCheckBudget(t *testing.T) {
+	oldResult := &commonResult{
+		Name: "old",
+		Size: 1000,
+		Packages: map[string]commonPackage{
+			"main": {Name: "main", Size: 100},
+		},
+		Sections: []commonSection{{Name: ".text", FileSize: 500}},
+	}
+	newResult := &commonResult{
+		Name: "new",
+		Size: 1200,
+		Packages: map[string]commonPackage{
+			"main": {
+				Name: "main",
+				Size: 150,
+				SubPackages: map[string]commonPackage{
+					"sub": {Name: "main/sub", Size: 50},
+				},
+			},
+		},
+		Sections: []commonSection{{Name: ".text", FileSize: 700}},
+	}
+
+	c := &budget.Config{
+		Packages: map[string]budget.Size{"main/sub": 40},
+		Sections: map[string]budget.Size{".text": 600},
+		Growth:   budget.Growth{MaxBytes: 100},
+	}
+
+	assert.Equal(t, []budget.Violation{
+		{Rule: budget.RulePackage, Target: "main/sub", Limit: 40, Actual: 50},
+		{Rule: budget.RuleSection, Target: ".text", Limit: 600, Actual: 700},
+		{Rule: budget.RuleGrowth, Target: "old -> new", Limit: 100, Actual: 200},
+	}, checkBudget(c, oldResult, newResult))
 }
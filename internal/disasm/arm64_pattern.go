@@ -0,0 +1,160 @@
+package disasm
+
+import (
+	"golang.org/x/arch/arm64/arm64asm"
+)
+
+type arm64PosInst struct {
+	pc   uint64
+	inst arm64asm.Inst
+}
+
+type arm64Pattern = pattern[arm64PosInst]
+
+const (
+	arm64MoveWideMask = 0x7f800000 // ignores the sf bit, matches both W and X forms
+	arm64MovzBits     = 0x52800000
+	arm64MovkBits     = 0x72800000
+)
+
+// arm64GetAddr resolves an ADRP+ADD pair into the absolute address it
+// materializes, and returns the register holding it.
+func arm64GetAddr(adrp, add arm64PosInst) (uint64, arm64asm.Reg, bool) {
+	if adrp.inst.Op != arm64asm.ADRP || add.inst.Op != arm64asm.ADD {
+		return 0, 0, false
+	}
+
+	pageReg, ok := adrp.inst.Args[0].(arm64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	pageOff, ok := adrp.inst.Args[1].(arm64asm.PCRel)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// only the immediate form, ADD <Xd|SP>, <Xn|SP>, #<imm>{, <shift>}
+	dst, ok := add.inst.Args[0].(arm64asm.RegSP)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := add.inst.Args[1].(arm64asm.RegSP)
+	if !ok || arm64asm.Reg(src) != pageReg {
+		return 0, 0, false
+	}
+	if _, ok = add.inst.Args[2].(arm64asm.ImmShift); !ok {
+		return 0, 0, false
+	}
+
+	// ImmShift hides its fields, read imm12 and the shift flag from the encoding.
+	enc := add.inst.Enc
+	imm := uint64((enc >> 10) & 0xfff)
+	if (enc>>22)&1 == 1 {
+		imm <<= 12
+	}
+
+	page := adrp.pc &^ 0xfff
+	return uint64(int64(page)+int64(pageOff)) + imm, arm64asm.Reg(dst), true
+}
+
+// arm64GetMoveWide decodes a MOVZ or MOVK (selected by opBits) into the
+// target register and the shifted 16-bit immediate.
+func arm64GetMoveWide(inst arm64asm.Inst, opBits uint32) (arm64asm.Reg, uint64, bool) {
+	if inst.Enc&arm64MoveWideMask != opBits {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(arm64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+
+	hw := (inst.Enc >> 21) & 0x3
+	imm := uint64((inst.Enc>>5)&0xffff) << (hw * 16)
+	return reg, imm, true
+}
+
+// arm64GetLen reads a string length built by MOVZ, optionally followed by
+// MOVK instructions filling the upper halfwords of the same register.
+func arm64GetLen(insts []arm64PosInst) (arm64asm.Reg, uint64, bool) {
+	reg, size, ok := arm64GetMoveWide(insts[0].inst, arm64MovzBits)
+	if !ok {
+		return 0, 0, false
+	}
+	for _, i := range insts[1:] {
+		kReg, part, ok := arm64GetMoveWide(i.inst, arm64MovkBits)
+		if !ok || kReg != reg {
+			return 0, 0, false
+		}
+		size |= part
+	}
+	if size == 0 {
+		return 0, 0, false
+	}
+	return reg, size, true
+}
+
+func arm64MatchAddrThenLen(insts []arm64PosInst, _ addrReader) *PossibleStr {
+	addr, addrReg, ok := arm64GetAddr(insts[0], insts[1])
+	if !ok {
+		return nil
+	}
+	lenReg, size, ok := arm64GetLen(insts[2:])
+	if !ok || lenReg == addrReg {
+		return nil
+	}
+	return &PossibleStr{
+		Addr: addr,
+		Size: size,
+	}
+}
+
+var arm64Patterns = []arm64Pattern{
+	{
+		windowSize: 4,
+		// 1.
+		//
+		//	main.go:74            0x10007c6c              f0000100                ADRP 143360(PC), R0
+		//	main.go:74            0x10007c70              91186000                ADD $1560, R0, R0
+		//	main.go:74            0x10007c74              d28003c1                MOVD $30, R1
+		//	main.go:74            0x10007c78              94001c22                CALL runtime.printstring(SB)
+		matchFunc: func(insts []arm64PosInst, _ addrReader) *PossibleStr {
+			// the length continues with a MOVK, leave it to pattern 2
+			if _, _, ok := arm64GetLen(insts[2:]); ok {
+				return nil
+			}
+			return arm64MatchAddrThenLen(insts[:3], nil)
+		},
+	},
+	{
+		windowSize: 4,
+		// 2. same as 1, but the length does not fit in a single halfword
+		//
+		//	main.go:74            0x10007c6c              f0000100                ADRP 143360(PC), R0
+		//	main.go:74            0x10007c70              91186000                ADD $1560, R0, R0
+		//	main.go:74            0x10007c74              d28acf01                MOVD $22136, R1
+		//	main.go:74            0x10007c78              f2a00021                MOVK $(1<<16), R1
+		matchFunc: arm64MatchAddrThenLen,
+	},
+	{
+		windowSize: 3,
+		// 3. the length is materialized before the pointer
+		//
+		//	main.go:74            0x10007c6c              d28003c1                MOVD $30, R1
+		//	main.go:74            0x10007c70              f0000100                ADRP 143360(PC), R0
+		//	main.go:74            0x10007c74              91186000                ADD $1560, R0, R0
+		matchFunc: func(insts []arm64PosInst, _ addrReader) *PossibleStr {
+			lenReg, size, ok := arm64GetLen(insts[:1])
+			if !ok {
+				return nil
+			}
+			addr, addrReg, ok := arm64GetAddr(insts[1], insts[2])
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
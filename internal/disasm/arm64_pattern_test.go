@@ -0,0 +1,66 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func wordsToCode(words ...uint32) []byte {
+	code := make([]byte, 0, len(words)*4)
+	for _, w := range words {
+		code = binary.LittleEndian.AppendUint32(code, w)
+	}
+	return code
+}
+
+func TestExtractArm64(t *testing.T) {
+	const (
+		adrp = 0xd0000000 // ADRP X0, +2 pages
+		add  = 0x91048c00 // ADD X0, X0, #0x123
+		movz = 0xd28003c1 // MOVZ X1, #0x1e
+		movk = 0xf2a00021 // MOVK X1, #0x1, LSL #16
+		nop  = 0xd503201f
+		ret  = 0xd65f03c0
+	)
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "adrp add movz",
+			code: wordsToCode(adrp, add, movz, ret),
+			want: []PossibleStr{{Addr: 0x12123, Size: 0x1e}},
+		},
+		{
+			name: "adrp add movz movk",
+			code: wordsToCode(adrp, add, movz, movk, ret),
+			want: []PossibleStr{{Addr: 0x12123, Size: 0x1001e}},
+		},
+		{
+			name: "movz adrp add",
+			code: wordsToCode(movz, nop, adrp, add),
+			want: []PossibleStr{{Addr: 0x12123, Size: 0x1e}},
+		},
+		{
+			name: "add on another register",
+			code: wordsToCode(adrp, 0x91048c22, movz, ret), // ADD X2, X1, #0x123
+			want: []PossibleStr{},
+		},
+		{
+			name: "truncated",
+			code: wordsToCode(adrp, add)[:6],
+			want: []PossibleStr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractArm64(tt.code, 0x10000, nil)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package disasm
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/arch/arm/armasm"
+)
+
+type armPosInst struct {
+	pc   uint64
+	inst armasm.Inst
+}
+
+type armPattern = pattern[armPosInst]
+
+// armGetPoolLoad matches LDR reg, [PC, #off], which the Go linker uses to load
+// addresses and large constants from the literal pool. Returns the loaded word.
+func armGetPoolLoad(pos armPosInst, read addrReader) (uint64, armasm.Reg, bool) {
+	inst := pos.inst
+	if inst.Op != armasm.LDR {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(armasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	mem, ok := inst.Args[1].(armasm.Mem)
+	if !ok || mem.Base != armasm.PC || mem.Mode != armasm.AddrOffset || mem.Sign != 0 {
+		return 0, 0, false
+	}
+
+	// PC reads as the address of the current instruction plus 8
+	poolAddr := uint64(int64(pos.pc) + 8 + int64(mem.Offset))
+	word, err := read(poolAddr, 4)
+	if err != nil || len(word) < 4 {
+		return 0, 0, false
+	}
+	return uint64(binary.LittleEndian.Uint32(word)), reg, true
+}
+
+// armGetMovImm matches MOV reg, #imm in both the rotated and the 16-bit form.
+func armGetMovImm(inst armasm.Inst) (uint64, armasm.Reg, bool) {
+	if inst.Op != armasm.MOV && inst.Op != armasm.MOVW {
+		return 0, 0, false
+	}
+	if inst.Args[2] != nil {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(armasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+
+	switch imm := inst.Args[1].(type) {
+	case armasm.Imm:
+		return uint64(imm), reg, true
+	case armasm.ImmAlt:
+		return uint64(imm.Imm()), reg, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// armGetLen reads a string length, either encoded as an immediate or loaded
+// from the literal pool when it does not fit.
+func armGetLen(pos armPosInst, read addrReader) (uint64, armasm.Reg, bool) {
+	size, reg, ok := armGetMovImm(pos.inst)
+	if !ok {
+		size, reg, ok = armGetPoolLoad(pos, read)
+	}
+	if !ok || size == 0 {
+		return 0, 0, false
+	}
+	return size, reg, true
+}
+
+// armGetStackStore matches STR reg, [SP, #off] and returns off and reg.
+func armGetStackStore(inst armasm.Inst) (int16, armasm.Reg, bool) {
+	if inst.Op != armasm.STR {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(armasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	mem, ok := inst.Args[1].(armasm.Mem)
+	if !ok || mem.Base != armasm.SP || mem.Mode != armasm.AddrOffset || mem.Sign != 0 {
+		return 0, 0, false
+	}
+	return mem.Offset, reg, true
+}
+
+var armPatterns = []armPattern{
+	{
+		windowSize: 4,
+		// 1. arguments passed on stack
+		//
+		//	main.go:74            0x8a7e4                 e59f0024                MOVW 0x24(R15), R0
+		//	main.go:74            0x8a7e8                 e58d0004                MOVW R0, 0x4(R13)
+		//	main.go:74            0x8a7ec                 e3a0001e                MOVW $30, R0
+		//	main.go:74            0x8a7f0                 e58d0008                MOVW R0, 0x8(R13)
+		//	main.go:74            0x8a7f4                 ebff8e41                CALL runtime.printstring(SB)
+		matchFunc: func(insts []armPosInst, read addrReader) *PossibleStr {
+			addr, addrReg, ok := armGetPoolLoad(insts[0], read)
+			if !ok {
+				return nil
+			}
+			ptrOff, reg, ok := armGetStackStore(insts[1].inst)
+			if !ok || reg != addrReg {
+				return nil
+			}
+
+			size, lenReg, ok := armGetLen(insts[2], read)
+			if !ok {
+				return nil
+			}
+			lenOff, reg, ok := armGetStackStore(insts[3].inst)
+			if !ok || reg != lenReg || lenOff != ptrOff+4 {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 2,
+		// 2. both halves kept in registers
+		//
+		//	main.go:74            0x8a7e4                 e59f0024                MOVW 0x24(R15), R0
+		//	main.go:74            0x8a7e8                 e3a0101e                MOVW $30, R1
+		matchFunc: func(insts []armPosInst, read addrReader) *PossibleStr {
+			addr, addrReg, ok := armGetPoolLoad(insts[0], read)
+			if !ok {
+				return nil
+			}
+			size, lenReg, ok := armGetLen(insts[1], read)
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
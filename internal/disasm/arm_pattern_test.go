@@ -0,0 +1,55 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractArm(t *testing.T) {
+	const (
+		ldr      = 0xe59f0024 // LDR R0, [PC, #0x24]
+		strPtr   = 0xe58d0004 // STR R0, [SP, #4]
+		movLen   = 0xe3a0001e // MOV R0, #30
+		strLen   = 0xe58d0008 // STR R0, [SP, #8]
+		strWrong = 0xe58d000c // STR R0, [SP, #12]
+		movR1    = 0xe3a0101e // MOV R1, #30
+	)
+
+	read := func(addr, size uint64) ([]byte, error) {
+		// pool slot of the LDR at 0x10000
+		assert.Equal(t, uint64(0x1002c), addr)
+		assert.Equal(t, uint64(4), size)
+		return binary.LittleEndian.AppendUint32(nil, 0x8a810), nil
+	}
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "stack args",
+			code: wordsToCode(ldr, strPtr, movLen, strLen),
+			want: []PossibleStr{{Addr: 0x8a810, Size: 30}},
+		},
+		{
+			name: "registers",
+			code: wordsToCode(ldr, movR1),
+			want: []PossibleStr{{Addr: 0x8a810, Size: 30}},
+		},
+		{
+			name: "mismatched stack slots",
+			code: wordsToCode(ldr, strPtr, movLen, strWrong),
+			want: []PossibleStr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractArm(tt.code, 0x10000, read)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
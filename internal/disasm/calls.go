@@ -0,0 +1,78 @@
+package disasm
+
+import (
+	"slices"
+
+	"golang.org/x/arch/arm64/arm64asm"
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// callFunc receives the target of a direct call.
+type callFunc func(target uint64)
+
+// callExtractorFunc is an extractorFunc also reporting the direct calls to call,
+// found in the same decoding pass as the strings.
+type callExtractorFunc func(code []byte, pc uint64, read addrReader, call callFunc) []PossibleStr
+
+var callExtractFuncs = map[string]callExtractorFunc{
+	"amd64": func(code []byte, pc uint64, read addrReader, call callFunc) []PossibleStr {
+		return extractX86(code, pc, 64, x86Patterns, read, call)
+	},
+	"386": func(code []byte, pc uint64, read addrReader, call callFunc) []PossibleStr {
+		return extractX86(code, pc, 32, i386Patterns, read, call)
+	},
+	"arm64": extractArm64WithCalls,
+}
+
+// SupportsCalls reports whether ExtractWithCalls finds the calls on the architecture.
+func (e *Extractor) SupportsCalls() bool {
+	return callExtractFuncs[e.goarch] != nil
+}
+
+// ExtractWithCalls is Extract also returning the sorted targets of the direct
+// calls of the function in [start, end), only the ones in the text section.
+// Calls through registers, like closures and interface methods, are not found,
+// and no call is found if the architecture is not supported.
+func (e *Extractor) ExtractWithCalls(start, end uint64) ([]PossibleStr, []uint64) {
+	extract := callExtractFuncs[e.goarch]
+	if extract == nil {
+		return e.Extract(start, end), nil
+	}
+
+	code, ok := e.code(start, end)
+	if !ok {
+		return nil, nil
+	}
+
+	var targets []uint64
+	strs := extract(code, start, e.raw.ReadAddr, func(target uint64) {
+		if e.textStart <= target && target < e.textEnd {
+			targets = append(targets, target)
+		}
+	})
+
+	slices.Sort(targets)
+	return strs, slices.Compact(targets)
+}
+
+// x86CallTarget returns the target of a CALL rel32 instruction.
+func x86CallTarget(inst x86asm.Inst, pc uint64, mode int) (uint64, bool) {
+	rel, ok := inst.Args[0].(x86asm.Rel)
+	if !ok || inst.Op != x86asm.CALL {
+		return 0, false
+	}
+	target := pc + uint64(inst.Len) + uint64(int64(rel))
+	if mode == 32 {
+		target &= 0xffffffff
+	}
+	return target, true
+}
+
+// arm64CallTarget returns the target of a BL instruction.
+func arm64CallTarget(inst arm64asm.Inst, pc uint64) (uint64, bool) {
+	rel, ok := inst.Args[0].(arm64asm.PCRel)
+	if !ok || inst.Op != arm64asm.BL {
+		return 0, false
+	}
+	return pc + uint64(int64(rel)), true
+}
@@ -0,0 +1,92 @@
+package disasm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collectCalls runs the extractor of goarch and returns the calls in the order found.
+func collectCalls(goarch string, code []byte, pc uint64) []uint64 {
+	var targets []uint64
+	callExtractFuncs[goarch](code, pc, func(_, _ uint64) ([]byte, error) {
+		return nil, errors.New("no data")
+	}, func(target uint64) {
+		targets = append(targets, target)
+	})
+	return targets
+}
+
+func TestExtractAmd64Calls(t *testing.T) {
+	code := []byte{
+		0xe8, 0xfb, 0x0f, 0x00, 0x00, // CALL 0x2000
+		0x48, 0x8b, 0x05, 0xf9, 0x0f, 0x00, 0x00, // MOVQ 0xff9(IP), AX
+		0xff, 0xd0, // CALL AX
+		0xe8, 0xed, 0xef, 0xff, 0xff, // CALL 0x0
+		0xe8, 0xe8, 0x0f, 0x00, 0x00, // CALL 0x2000
+	}
+
+	assert.Equal(t, []uint64{0x2000, 0x0, 0x2000}, collectCalls("amd64", code, 0x1000))
+}
+
+func TestExtract386Calls(t *testing.T) {
+	code := []byte{0xe8, 0xfb, 0xff, 0xff, 0xff} // CALL 0x1000
+
+	assert.Equal(t, []uint64{0x1000}, collectCalls("386", code, 0x1000))
+}
+
+func TestExtractArm64Calls(t *testing.T) {
+	const (
+		blForward  = 0x94000040 // BL +0x100
+		blBackward = 0x97fffffe // BL -8
+		blr        = 0xd63f0000 // BLR X0
+	)
+
+	got := collectCalls("arm64", wordsToCode(blForward, blr, blBackward), 0x1000)
+	assert.Equal(t, []uint64{0x1100, 0x1000}, got)
+}
+
+func TestExtractor_ExtractWithCalls(t *testing.T) {
+	code := []byte{
+		0xe8, 0x0b, 0x00, 0x00, 0x00, // CALL 0x1010
+		0xe8, 0xf6, 0x0f, 0x00, 0x00, // CALL 0x2000, outside text
+		0xe8, 0x01, 0x00, 0x00, 0x00, // CALL 0x1010
+		0x90, // NOP
+		0xc3, // RET at 0x1010
+	}
+	w := TestFileWrapper{arch: "amd64", textStart: 0x1000, text: code}
+	e := Extractor{raw: w, goarch: "amd64", text: code, textStart: 0x1000, textEnd: 0x1000 + uint64(len(code)), extractor: extractAmd64}
+
+	assert.True(t, e.SupportsCalls())
+	strs, calls := e.ExtractWithCalls(0x1000, 0x1010)
+	assert.Empty(t, strs)
+	assert.Equal(t, []uint64{0x1010}, calls)
+
+	strs, calls = e.ExtractWithCalls(0x0, 0x1010)
+	assert.Nil(t, strs)
+	assert.Nil(t, calls)
+
+	e.goarch = "riscv64"
+	e.extractor = extractRiscv64
+	assert.False(t, e.SupportsCalls())
+	_, calls = e.ExtractWithCalls(0x1000, 0x1010)
+	assert.Nil(t, calls)
+}
+
+// TestExtractWithCallsStrings checks the strings are the same as Extract finds.
+func TestExtractWithCallsStrings(t *testing.T) {
+	code := []byte{
+		0xe8, 0x0b, 0x00, 0x00, 0x00, // CALL 0x1010
+		0x48, 0x8d, 0x05, 0xf4, 0x0f, 0x00, 0x00, // LEAQ 0xff4(IP), AX
+		0xbb, 0x05, 0x00, 0x00, 0x00, // MOVL $5, BX
+		0xc3, // RET
+	}
+	w := TestFileWrapper{arch: "amd64", textStart: 0x1000, text: code}
+	e := Extractor{raw: w, goarch: "amd64", text: code, textStart: 0x1000, textEnd: 0x1000 + uint64(len(code)), extractor: extractAmd64}
+
+	strs, calls := e.ExtractWithCalls(0x1000, e.textEnd)
+	assert.Equal(t, e.Extract(0x1000, e.textEnd), strs)
+	assert.Equal(t, []PossibleStr{{Addr: 0x2000, Size: 5}}, strs)
+	assert.Equal(t, []uint64{0x1010}, calls)
+}
@@ -1,6 +1,15 @@
 package disasm
 
 import (
+	"encoding/binary"
+	"sync"
+
+	"golang.org/x/arch/arm/armasm"
+	"golang.org/x/arch/arm64/arm64asm"
+	"golang.org/x/arch/loong64/loong64asm"
+	"golang.org/x/arch/ppc64/ppc64asm"
+	"golang.org/x/arch/riscv64/riscv64asm"
+	"golang.org/x/arch/s390x/s390xasm"
 	"golang.org/x/arch/x86/x86asm"
 
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
@@ -8,15 +17,56 @@ import (
 
 var extractFuncs = map[string]extractorFunc{
 	"amd64": extractAmd64,
+	"arm64": extractArm64,
+	"386":   extract386,
+	"arm":   extractArm,
+
+	"riscv64": extractRiscv64,
+	"ppc64":   extractPpc64,
+	"ppc64le": extractPpc64le,
+	"s390x":   extractS390x,
+	"loong64": extractLoong64,
+}
+
+// pattern matches a fixed size window of decoded instructions.
+type pattern[I any] struct {
+	windowSize int
+	matchFunc  func([]I, addrReader) *PossibleStr
 }
 
-func extractAmd64(code []byte, pc uint64) []PossibleStr {
+// matchPatterns slides every pattern over insts and collects the deduplicated candidates.
+func matchPatterns[I any](insts []I, patterns []pattern[I], read addrReader) []PossibleStr {
 	resultSet := utils.NewSet[PossibleStr]()
 
+	for i := range len(insts) {
+		for _, p := range patterns {
+			if len(insts) < i+p.windowSize {
+				continue
+			}
+			matchRet := p.matchFunc(insts[i:i+p.windowSize], read)
+			if matchRet != nil {
+				resultSet.Add(*matchRet)
+			}
+		}
+	}
+
+	return resultSet.ToSlice()
+}
+
+func extractAmd64(code []byte, pc uint64, read addrReader) []PossibleStr {
+	return extractX86(code, pc, 64, x86Patterns, read, nil)
+}
+
+func extract386(code []byte, pc uint64, read addrReader) []PossibleStr {
+	return extractX86(code, pc, 32, i386Patterns, read, nil)
+}
+
+// extractX86 also reports the direct calls to call if it is not nil.
+func extractX86(code []byte, pc uint64, mode int, patterns []x86Pattern, read addrReader, call callFunc) []PossibleStr {
 	insts := make([]x86PosInst, 0)
 
 	for len(code) > 0 {
-		inst, err := x86asm.Decode(code, 64)
+		inst, err := x86asm.Decode(code, mode)
 		size := 0
 		if err != nil || inst.Len == 0 || inst.Op == 0 {
 			size = 1
@@ -25,22 +75,183 @@ func extractAmd64(code []byte, pc uint64) []PossibleStr {
 			if inst.Op != x86asm.NOP {
 				insts = append(insts, x86PosInst{pc: pc, inst: inst})
 			}
+			if call != nil {
+				if target, ok := x86CallTarget(inst, pc, mode); ok {
+					call(target)
+				}
+			}
 		}
 		code = code[size:]
 		pc += uint64(size)
 	}
 
-	for i := range len(insts) {
-		for _, p := range x86Patterns {
-			if len(insts) < i+p.windowSize {
-				continue
+	return matchPatterns(insts, patterns, read)
+}
+
+func extractArm64(code []byte, pc uint64, read addrReader) []PossibleStr {
+	return extractArm64WithCalls(code, pc, read, nil)
+}
+
+// extractArm64WithCalls also reports the direct calls to call if it is not nil.
+func extractArm64WithCalls(code []byte, pc uint64, read addrReader, call callFunc) []PossibleStr {
+	insts := make([]arm64PosInst, 0)
+
+	// arm64 instructions are fixed 4 bytes, undecodable words are skipped as a whole
+	lazyDecodeMu.Lock()
+	for len(code) >= 4 {
+		inst, err := arm64asm.Decode(code)
+		if err == nil && inst.Op != arm64asm.NOP {
+			insts = append(insts, arm64PosInst{pc: pc, inst: inst})
+		}
+		code = code[4:]
+		pc += 4
+	}
+	lazyDecodeMu.Unlock()
+
+	if call != nil {
+		for _, i := range insts {
+			if target, ok := arm64CallTarget(i.inst, i.pc); ok {
+				call(target)
 			}
-			matchRet := p.matchFunc(insts[i : i+p.windowSize])
-			if matchRet != nil {
-				resultSet.Add(*matchRet)
+		}
+	}
+
+	return matchPatterns(insts, arm64Patterns, read)
+}
+
+// arm64asm, armasm, ppc64asm, s390xasm, riscv64asm and loong64asm record
+// decoder coverage in a package level slice on every call, some of them
+// allocating it lazily on the first one, so decoding with them must not run
+// concurrently.
+var lazyDecodeMu sync.Mutex
+
+func extractArm(code []byte, pc uint64, read addrReader) []PossibleStr {
+	insts := make([]armPosInst, 0)
+
+	lazyDecodeMu.Lock()
+	for len(code) >= 4 {
+		inst, err := armasm.Decode(code, armasm.ModeARM)
+		if err == nil && inst.Op != armasm.NOP {
+			insts = append(insts, armPosInst{pc: pc, inst: inst})
+		}
+		code = code[4:]
+		pc += 4
+	}
+	lazyDecodeMu.Unlock()
+
+	return matchPatterns(insts, armPatterns, read)
+}
+
+func extractRiscv64(code []byte, pc uint64, read addrReader) []PossibleStr {
+	insts := make([]riscv64PosInst, 0)
+
+	lazyDecodeMu.Lock()
+	for len(code) >= 2 {
+		inst, err := riscv64asm.Decode(code)
+		size := inst.Len
+		if err != nil || size == 0 {
+			// the low bits tell a compressed instruction from a full one
+			size = 2
+			if code[0]&3 == 3 {
+				size = 4
 			}
+		} else {
+			insts = append(insts, riscv64PosInst{pc: pc, inst: inst})
+		}
+		if size > len(code) {
+			break
 		}
+		code = code[size:]
+		pc += uint64(size)
 	}
+	lazyDecodeMu.Unlock()
 
-	return resultSet.ToSlice()
+	return matchPatterns(insts, riscv64Patterns, read)
+}
+
+func extractPpc64(code []byte, pc uint64, read addrReader) []PossibleStr {
+	return extractPpc64WithOrder(code, pc, binary.BigEndian, read)
+}
+
+func extractPpc64le(code []byte, pc uint64, read addrReader) []PossibleStr {
+	return extractPpc64WithOrder(code, pc, binary.LittleEndian, read)
+}
+
+func extractPpc64WithOrder(code []byte, pc uint64, order binary.ByteOrder, read addrReader) []PossibleStr {
+	insts := make([]ppc64PosInst, 0)
+	entry := pc
+
+	lazyDecodeMu.Lock()
+	for len(code) >= 4 {
+		inst, err := ppc64asm.Decode(code, order)
+		size := inst.Len
+		if size == 0 || size > len(code) {
+			size = 4
+		}
+		if err == nil && inst.Op != ppc64asm.NOP {
+			insts = append(insts, ppc64PosInst{pc: pc, inst: inst})
+		}
+		code = code[size:]
+		pc += uint64(size)
+	}
+	lazyDecodeMu.Unlock()
+
+	if toc, ok := ppc64EntryTOC(insts, entry); ok {
+		for i := range insts {
+			insts[i].toc = toc
+		}
+	}
+
+	return matchPatterns(insts, ppc64Patterns, read)
+}
+
+func extractS390x(code []byte, pc uint64, read addrReader) []PossibleStr {
+	insts := make([]s390xPosInst, 0)
+
+	lazyDecodeMu.Lock()
+	for len(code) >= 2 {
+		// s390xasm does not check the buffer against the length encoded
+		// in the first two bits before slicing it
+		size := s390xInstLen(code[0])
+		if size > len(code) {
+			break
+		}
+		inst, err := s390xasm.Decode(code[:size])
+		if err == nil && inst.Op != 0 {
+			insts = append(insts, s390xPosInst{pc: pc, inst: inst})
+		}
+		code = code[size:]
+		pc += uint64(size)
+	}
+	lazyDecodeMu.Unlock()
+
+	return matchPatterns(insts, s390xPatterns, read)
+}
+
+func s390xInstLen(first byte) int {
+	switch first >> 6 {
+	case 0:
+		return 2
+	case 3:
+		return 6
+	default:
+		return 4
+	}
+}
+
+func extractLoong64(code []byte, pc uint64, read addrReader) []PossibleStr {
+	insts := make([]loong64PosInst, 0)
+
+	lazyDecodeMu.Lock()
+	for len(code) >= 4 {
+		inst, err := loong64asm.Decode(code)
+		if err == nil {
+			insts = append(insts, loong64PosInst{pc: pc, inst: inst})
+		}
+		code = code[4:]
+		pc += 4
+	}
+	lazyDecodeMu.Unlock()
+
+	return matchPatterns(insts, loong64Patterns, read)
 }
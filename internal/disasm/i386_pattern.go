@@ -0,0 +1,168 @@
+package disasm
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// i386GetAbsMem matches an operand addressing an absolute location,
+// which is how 386 code refers to symbols without PC-relative addressing.
+func i386GetAbsMem(arg x86asm.Arg) (uint64, bool) {
+	mem, ok := arg.(x86asm.Mem)
+	if !ok || mem.Segment != 0 || mem.Base != 0 || mem.Index != 0 {
+		return 0, false
+	}
+	return uint64(uint32(mem.Disp)), true
+}
+
+// i386GetLea matches LEAL sym(SB), reg and returns the symbol address and reg.
+func i386GetLea(inst x86asm.Inst) (uint64, x86asm.Reg, bool) {
+	if inst.Op != x86asm.LEA || x86CountNotNilArgs(inst.Args) != 2 {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(x86asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	addr, ok := i386GetAbsMem(inst.Args[1])
+	if !ok {
+		return 0, 0, false
+	}
+	return addr, reg, true
+}
+
+// i386GetAbsLoad matches MOVL sym(SB), reg and returns the symbol address and reg.
+func i386GetAbsLoad(inst x86asm.Inst) (uint64, x86asm.Reg, bool) {
+	if inst.Op != x86asm.MOV || x86CountNotNilArgs(inst.Args) != 2 || inst.DataSize != 32 {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(x86asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	addr, ok := i386GetAbsMem(inst.Args[1])
+	if !ok {
+		return 0, 0, false
+	}
+	return addr, reg, true
+}
+
+// i386GetStackStore matches MOVL src, off(SP) and returns off and src.
+func i386GetStackStore(inst x86asm.Inst) (int64, x86asm.Arg, bool) {
+	if inst.Op != x86asm.MOV || x86CountNotNilArgs(inst.Args) != 2 {
+		return 0, nil, false
+	}
+	mem, ok := inst.Args[0].(x86asm.Mem)
+	if !ok || mem.Base != x86asm.ESP || mem.Index != 0 {
+		return 0, nil, false
+	}
+	return mem.Disp, inst.Args[1], true
+}
+
+var i386Patterns = []x86Pattern{
+	{
+		windowSize: 3,
+		// 1. arguments passed on stack
+		//
+		//	main.go:74            0x80a5f4e               8d05d0c10e08            LEAL go:string.*+3024(SB), AX
+		//	main.go:74            0x80a5f54               890424                  MOVL AX, 0(SP)
+		//	main.go:74            0x80a5f57               c74424041e000000        MOVL $0x1e, 0x4(SP)
+		//	main.go:74            0x80a5f5f               e8bc0bfaff              CALL runtime.printstring(SB)
+		matchFunc: func(insts []x86PosInst, _ addrReader) *PossibleStr {
+			addr, reg, ok := i386GetLea(insts[0].inst)
+			if !ok {
+				return nil
+			}
+
+			ptrOff, src, ok := i386GetStackStore(insts[1].inst)
+			if !ok || src != reg {
+				return nil
+			}
+
+			lenOff, _, ok := i386GetStackStore(insts[2].inst)
+			if !ok || lenOff != ptrOff+4 {
+				return nil
+			}
+			size, ok := x86GetMovImm(insts[2].inst)
+			if !ok {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 2,
+		// 2. both halves kept in registers
+		//
+		//	main.go:74            0x80a5f4e               8d05d0c10e08            LEAL go:string.*+3024(SB), AX
+		//	main.go:74            0x80a5f54               b91e000000              MOVL $0x1e, CX
+		matchFunc: func(insts []x86PosInst, _ addrReader) *PossibleStr {
+			addr, reg, ok := i386GetLea(insts[0].inst)
+			if !ok {
+				return nil
+			}
+
+			dst, ok := insts[1].inst.Args[0].(x86asm.Reg)
+			if !ok || dst == reg {
+				return nil
+			}
+			size, ok := x86GetMovImm(insts[1].inst)
+			if !ok {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 2,
+		// 3. string header stored in read-write data
+		//
+		//	main.go:79            0x80a5fa4               a1e0a31308              MOVL main.GlobalString(SB), AX
+		//	main.go:79            0x80a5fa9               8b0de4a31308            MOVL main.GlobalString+4(SB), CX
+		matchFunc: func(insts []x86PosInst, read addrReader) *PossibleStr {
+			firstAddr, firstReg, ok := i386GetAbsLoad(insts[0].inst)
+			if !ok {
+				return nil
+			}
+			secondAddr, secondReg, ok := i386GetAbsLoad(insts[1].inst)
+			if !ok || firstReg == secondReg {
+				return nil
+			}
+
+			var headerAddr uint64
+			switch {
+			case secondAddr == firstAddr+4:
+				headerAddr = firstAddr
+			case firstAddr == secondAddr+4:
+				headerAddr = secondAddr
+			default:
+				return nil
+			}
+
+			header, err := read(headerAddr, 8)
+			if err != nil || len(header) < 8 {
+				return nil
+			}
+
+			ptr := uint64(binary.LittleEndian.Uint32(header[:4]))
+			size := uint64(binary.LittleEndian.Uint32(header[4:]))
+			if ptr == 0 || size == 0 {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: ptr,
+				Size: size,
+			}
+		},
+	},
+}
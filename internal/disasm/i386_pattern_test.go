@@ -0,0 +1,67 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract386(t *testing.T) {
+	header := binary.LittleEndian.AppendUint32(nil, 0x80ec1d0)
+	header = binary.LittleEndian.AppendUint32(header, 0x1e)
+
+	read := func(addr, size uint64) ([]byte, error) {
+		assert.Equal(t, uint64(0x813a3e0), addr)
+		assert.Equal(t, uint64(8), size)
+		return header, nil
+	}
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "lea with stack args",
+			code: []byte{
+				0x8d, 0x05, 0xd0, 0xc1, 0x0e, 0x08, // LEAL 0x80ec1d0, AX
+				0x89, 0x04, 0x24, // MOVL AX, 0(SP)
+				0xc7, 0x44, 0x24, 0x04, 0x1e, 0x00, 0x00, 0x00, // MOVL $0x1e, 0x4(SP)
+			},
+			want: []PossibleStr{{Addr: 0x80ec1d0, Size: 0x1e}},
+		},
+		{
+			name: "lea with register",
+			code: []byte{
+				0x8d, 0x05, 0xd0, 0xc1, 0x0e, 0x08, // LEAL 0x80ec1d0, AX
+				0xb9, 0x1e, 0x00, 0x00, 0x00, // MOVL $0x1e, CX
+			},
+			want: []PossibleStr{{Addr: 0x80ec1d0, Size: 0x1e}},
+		},
+		{
+			name: "mismatched stack slots",
+			code: []byte{
+				0x8d, 0x05, 0xd0, 0xc1, 0x0e, 0x08, // LEAL 0x80ec1d0, AX
+				0x89, 0x04, 0x24, // MOVL AX, 0(SP)
+				0xc7, 0x44, 0x24, 0x08, 0x1e, 0x00, 0x00, 0x00, // MOVL $0x1e, 0x8(SP)
+			},
+			want: []PossibleStr{},
+		},
+		{
+			name: "global string",
+			code: []byte{
+				0xa1, 0xe0, 0xa3, 0x13, 0x08, // MOVL 0x813a3e0, AX
+				0x8b, 0x0d, 0xe4, 0xa3, 0x13, 0x08, // MOVL 0x813a3e4, CX
+			},
+			want: []PossibleStr{{Addr: 0x80ec1d0, Size: 0x1e}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extract386(tt.code, 0x8049000, read)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"unicode/utf8"
 
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
@@ -15,7 +16,11 @@ type PossibleStr struct {
 	Size uint64
 }
 
-type extractorFunc func(code []byte, pc uint64) []PossibleStr
+// addrReader reads the file content mapped at addr, used by patterns that load
+// the string header from data instead of materializing it in code.
+type addrReader func(addr, size uint64) ([]byte, error)
+
+type extractorFunc func(code []byte, pc uint64, read addrReader) []PossibleStr
 
 type validator func(addr, size uint64) bool
 
@@ -26,6 +31,7 @@ type Extractor struct {
 	textStart  uint64        // start PC of text
 	textEnd    uint64        // end PC of text
 	goarch     string        // GOARCH string
+	ptrSize    int           // pointer size of goarch
 	validators []validator   // validators for possible strings
 	extractor  extractorFunc // disassembler function for goarch
 }
@@ -58,6 +64,7 @@ func NewExtractor(rawFile wrapper.RawFileWrapper,
 		textStart: textStart,
 		textEnd:   textStart + uint64(len(text)),
 		goarch:    goarch,
+		ptrSize:   archPtrSize(goarch),
 		extractor: extractFunc,
 	}
 
@@ -74,7 +81,24 @@ func NewExtractor(rawFile wrapper.RawFileWrapper,
 	return extractor, nil
 }
 
+func archPtrSize(goarch string) int {
+	switch goarch {
+	case "386", "arm", "mips", "mipsle":
+		return 4
+	default:
+		return 8
+	}
+}
+
 func (e *Extractor) Validate(addr, size uint64) bool {
+	if addr+size < addr {
+		return false
+	}
+	if e.ptrSize == 4 && addr+size > math.MaxUint32+1 {
+		// out of the 32-bit address space
+		return false
+	}
+
 	for _, v := range e.validators {
 		if !v(addr, size) {
 			return false
@@ -84,14 +108,22 @@ func (e *Extractor) Validate(addr, size uint64) bool {
 }
 
 func (e *Extractor) Extract(start, end uint64) []PossibleStr {
-	if start < e.textStart || end > e.textEnd || start > end {
-		slog.Debug("skipping function outside text section", "start", fmt.Sprintf("%#x", start), "end", fmt.Sprintf("%#x", end), "textStart", fmt.Sprintf("%#x", e.textStart), "textEnd", fmt.Sprintf("%#x", e.textEnd))
+	code, ok := e.code(start, end)
+	if !ok {
 		return nil
 	}
 
-	code := e.text[start-e.textStart : end-e.textStart]
+	return e.extractor(code, start, e.raw.ReadAddr)
+}
+
+// code returns the instructions in [start, end), false if it is not in the text section.
+func (e *Extractor) code(start, end uint64) ([]byte, bool) {
+	if start < e.textStart || end > e.textEnd || start > end {
+		slog.Debug("skipping function outside text section", "start", fmt.Sprintf("%#x", start), "end", fmt.Sprintf("%#x", end), "textStart", fmt.Sprintf("%#x", e.textStart), "textEnd", fmt.Sprintf("%#x", e.textEnd))
+		return nil, false
+	}
 
-	return e.extractor(code, start)
+	return e.text[start-e.textStart : end-e.textStart], true
 }
 
 func (e *Extractor) checkAddrString(addr, size uint64) bool {
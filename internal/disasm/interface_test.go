@@ -3,6 +3,8 @@ package disasm
 import (
 	"debug/dwarf"
 	"errors"
+	"math"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -96,3 +98,43 @@ func TestExtractor_LoadAddrString(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+func TestExtractor_Validate(t *testing.T) {
+	t.Run("overflow", func(t *testing.T) {
+		extractor := Extractor{ptrSize: 8}
+		assert.False(t, extractor.Validate(math.MaxUint64-1, 4))
+	})
+
+	t.Run("beyond 32-bit address space", func(t *testing.T) {
+		extractor := Extractor{ptrSize: 4}
+		assert.False(t, extractor.Validate(math.MaxUint32-1, 4))
+	})
+
+	t.Run("end of 32-bit address space", func(t *testing.T) {
+		extractor := Extractor{ptrSize: 4}
+		assert.True(t, extractor.Validate(math.MaxUint32-3, 4))
+	})
+}
+
+// TestExtractConcurrently runs every extractor in parallel like Disasm does,
+// some decoders write package level state and are serialized.
+func TestExtractConcurrently(t *testing.T) {
+	// riscv64, loong64 and arm64 words: adrp, add and bl
+	code := wordsToCode(0x00032517, 0xc2c50513, 0x01e00593, 0x3c60100d, 0x38632b3c,
+		0x90000000, 0x91000000, 0x94000001)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		for _, extract := range extractFuncs {
+			wg.Go(func() {
+				extract(code, 0x1000, nil)
+			})
+		}
+		for _, extract := range callExtractFuncs {
+			wg.Go(func() {
+				extract(code, 0x1000, nil, func(uint64) {})
+			})
+		}
+	}
+	wg.Wait()
+}
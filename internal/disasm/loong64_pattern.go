@@ -0,0 +1,129 @@
+package disasm
+
+import (
+	"golang.org/x/arch/loong64/loong64asm"
+)
+
+type loong64PosInst struct {
+	pc   uint64
+	inst loong64asm.Inst
+}
+
+type loong64Pattern = pattern[loong64PosInst]
+
+// loong64GetAddr resolves a PCALAU12I+ADDI.D pair into the absolute address
+// it materializes, and returns the register holding it.
+func loong64GetAddr(pcalau12i, addi loong64PosInst) (uint64, loong64asm.Reg, bool) {
+	if pcalau12i.inst.Op != loong64asm.PCALAU12I || addi.inst.Op != loong64asm.ADDI_D {
+		return 0, 0, false
+	}
+
+	reg, ok := pcalau12i.inst.Args[0].(loong64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	hi, ok := pcalau12i.inst.Args[1].(loong64asm.Simm32)
+	if !ok {
+		return 0, 0, false
+	}
+
+	dst, ok := addi.inst.Args[0].(loong64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := addi.inst.Args[1].(loong64asm.Reg)
+	if !ok || src != reg {
+		return 0, 0, false
+	}
+	lo, ok := addi.inst.Args[2].(loong64asm.Simm16)
+	if !ok {
+		return 0, 0, false
+	}
+
+	page := pcalau12i.pc &^ 0xfff
+	off := int64(hi.Imm)<<12 + int64(lo.Imm)
+	return uint64(int64(page) + off), dst, true
+}
+
+// loong64GetLen matches the expansions of MOVV $imm, reg with a small
+// positive immediate, ORI reg, R0, imm or ADDI reg, R0, imm.
+func loong64GetLen(inst loong64asm.Inst) (uint64, loong64asm.Reg, bool) {
+	reg, ok := inst.Args[0].(loong64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := inst.Args[1].(loong64asm.Reg)
+	if !ok || src != loong64asm.R0 {
+		return 0, 0, false
+	}
+
+	var size uint64
+	switch inst.Op {
+	case loong64asm.ORI:
+		imm, ok := inst.Args[2].(loong64asm.Uimm)
+		if !ok {
+			return 0, 0, false
+		}
+		size = uint64(imm.Imm)
+	case loong64asm.ADDI_D, loong64asm.ADDI_W:
+		imm, ok := inst.Args[2].(loong64asm.Simm16)
+		if !ok || imm.Imm <= 0 {
+			return 0, 0, false
+		}
+		size = uint64(imm.Imm)
+	default:
+		return 0, 0, false
+	}
+
+	if size == 0 {
+		return 0, 0, false
+	}
+	return size, reg, true
+}
+
+var loong64Patterns = []loong64Pattern{
+	{
+		windowSize: 3,
+		// 1.
+		//
+		//	main.go:74            0x8e3ac                 1a000644                PCALAU12I $50, R4
+		//	main.go:74            0x8e3b0                 02f0d084                ADDV $-972, R4
+		//	main.go:74            0x8e3b4                 03807805                MOVW $30, R5
+		matchFunc: func(insts []loong64PosInst, _ addrReader) *PossibleStr {
+			addr, addrReg, ok := loong64GetAddr(insts[0], insts[1])
+			if !ok {
+				return nil
+			}
+			size, lenReg, ok := loong64GetLen(insts[2].inst)
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 3,
+		// 2. the length is materialized before the pointer
+		//
+		//	main.go:74            0x8e3ac                 03807805                MOVW $30, R5
+		//	main.go:74            0x8e3b0                 1a000644                PCALAU12I $50, R4
+		//	main.go:74            0x8e3b4                 02f0d084                ADDV $-972, R4
+		matchFunc: func(insts []loong64PosInst, _ addrReader) *PossibleStr {
+			size, lenReg, ok := loong64GetLen(insts[0].inst)
+			if !ok {
+				return nil
+			}
+			addr, addrReg, ok := loong64GetAddr(insts[1], insts[2])
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
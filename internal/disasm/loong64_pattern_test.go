@@ -0,0 +1,45 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLoong64(t *testing.T) {
+	const (
+		pcalau12i = 0x1a000644 // PCALAU12I $50, R4
+		addi      = 0x02f0d084 // ADDV $-972, R4
+		ori       = 0x03807805 // MOVW $30, R5
+		addiLen   = 0x02c07805 // ADDV $30, R0, R5
+	)
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "address then ori length",
+			code: wordsToCode(pcalau12i, addi, ori),
+			want: []PossibleStr{{Addr: 0xbfc34, Size: 30}},
+		},
+		{
+			name: "address then addi length",
+			code: wordsToCode(pcalau12i, addi, addiLen),
+			want: []PossibleStr{{Addr: 0xbfc34, Size: 30}},
+		},
+		{
+			name: "length then address",
+			code: wordsToCode(ori, pcalau12i, addi),
+			want: []PossibleStr{{Addr: 0xbfc34, Size: 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractLoong64(tt.code, 0x8e3ac, nil)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package disasm
+
+import (
+	"golang.org/x/arch/ppc64/ppc64asm"
+)
+
+type ppc64PosInst struct {
+	pc   uint64
+	inst ppc64asm.Inst
+	toc  uint64 // value of R2 set by the function prologue, 0 if unknown
+}
+
+type ppc64Pattern = pattern[ppc64PosInst]
+
+// ppc64EntryTOC returns the TOC pointer the global entry prologue of position
+// independent code computes into R2 from the entry address in R12,
+//
+//	ADDIS R12,$hi,R2
+//	ADD R2,$lo,R2
+func ppc64EntryTOC(insts []ppc64PosInst, entry uint64) (uint64, bool) {
+	if len(insts) < 2 || insts[0].pc != entry || insts[0].inst.Op != ppc64asm.ADDIS || insts[1].inst.Op != ppc64asm.ADDI {
+		return 0, false
+	}
+	if insts[0].inst.Args[0] != ppc64asm.R2 || insts[0].inst.Args[1] != ppc64asm.R12 ||
+		insts[1].inst.Args[0] != ppc64asm.R2 || insts[1].inst.Args[1] != ppc64asm.R2 {
+		return 0, false
+	}
+	hi, ok := insts[0].inst.Args[2].(ppc64asm.Imm)
+	if !ok {
+		return 0, false
+	}
+	lo, ok := insts[1].inst.Args[2].(ppc64asm.Imm)
+	if !ok {
+		return 0, false
+	}
+	return uint64(int64(entry) + int64(hi)<<16 + int64(lo)), true
+}
+
+// ppc64GetAddr resolves an ADDIS+ADDI pair into the address it materializes,
+// and returns the register holding it. The base of ADDIS is either 0, decoded
+// as LIS, or R2 once the prologue has set it to the TOC pointer. Other bases
+// are not known statically.
+func ppc64GetAddr(addis, addi ppc64PosInst) (uint64, ppc64asm.Reg, bool) {
+	var (
+		base uint64
+		hi   ppc64asm.Imm
+		ok   bool
+	)
+	switch addis.inst.Op {
+	case ppc64asm.LIS:
+		hi, ok = addis.inst.Args[1].(ppc64asm.Imm)
+	case ppc64asm.ADDIS:
+		if addis.inst.Args[1] != ppc64asm.R2 || addis.toc == 0 {
+			return 0, 0, false
+		}
+		base = addis.toc
+		hi, ok = addis.inst.Args[2].(ppc64asm.Imm)
+	}
+	if !ok || addi.inst.Op != ppc64asm.ADDI {
+		return 0, 0, false
+	}
+
+	reg, ok := addis.inst.Args[0].(ppc64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+
+	dst, ok := addi.inst.Args[0].(ppc64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := addi.inst.Args[1].(ppc64asm.Reg)
+	if !ok || src != reg {
+		return 0, 0, false
+	}
+	lo, ok := addi.inst.Args[2].(ppc64asm.Imm)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return uint64(int64(base) + int64(hi)<<16 + int64(lo)), dst, true
+}
+
+// ppc64GetLen matches LI reg, imm, the expansion of MOVD $imm, reg.
+func ppc64GetLen(inst ppc64asm.Inst) (uint64, ppc64asm.Reg, bool) {
+	if inst.Op != ppc64asm.LI {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(ppc64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	imm, ok := inst.Args[1].(ppc64asm.Imm)
+	if !ok || imm <= 0 {
+		return 0, 0, false
+	}
+	return uint64(imm), reg, true
+}
+
+var ppc64Patterns = []ppc64Pattern{
+	{
+		windowSize: 3,
+		// 1.
+		//
+		//	main.go:74            0x10086f60              3c60100d                ADDIS $0,$4109,R3
+		//	main.go:74            0x10086f64              38632b3c                ADD R3,$11068,R3
+		//	main.go:74            0x10086f68              3880001e                MOVD $30,R4
+		//
+		// or with the TOC pointer in position independent code
+		//
+		//	main.go:8             0xba924                 3c62fff0                ADDIS R2,$-16,R3
+		//	main.go:8             0xba928                 38637778                ADD R3,$30584,R3
+		//	main.go:8             0xba92c                 38800002                MOVD $2,R4
+		matchFunc: func(insts []ppc64PosInst, _ addrReader) *PossibleStr {
+			addr, addrReg, ok := ppc64GetAddr(insts[0], insts[1])
+			if !ok {
+				return nil
+			}
+			size, lenReg, ok := ppc64GetLen(insts[2].inst)
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 3,
+		// 2. the length is materialized before the pointer
+		//
+		//	main.go:74            0x10086f60              3880001e                MOVD $30,R4
+		//	main.go:74            0x10086f64              3c60100d                ADDIS $0,$4109,R3
+		//	main.go:74            0x10086f68              38632b3c                ADD R3,$11068,R3
+		matchFunc: func(insts []ppc64PosInst, _ addrReader) *PossibleStr {
+			size, lenReg, ok := ppc64GetLen(insts[0].inst)
+			if !ok {
+				return nil
+			}
+			addr, addrReg, ok := ppc64GetAddr(insts[1], insts[2])
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
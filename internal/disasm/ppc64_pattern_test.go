@@ -0,0 +1,66 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPpc64(t *testing.T) {
+	const (
+		lis  = 0x3c60100d // ADDIS $0,$4109,R3
+		addi = 0x38632b3c // ADD R3,$11068,R3
+		li   = 0x3880001e // MOVD $30,R4
+	)
+
+	tests := []struct {
+		name  string
+		words []uint32
+	}{
+		{
+			name:  "address then length",
+			words: []uint32{lis, addi, li},
+		},
+		{
+			name:  "length then address",
+			words: []uint32{li, lis, addi},
+		},
+	}
+
+	want := []PossibleStr{{Addr: 0x100d2b3c, Size: 30}}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" le", func(t *testing.T) {
+			got := extractPpc64le(wordsToCode(tt.words...), 0x10086f60, nil)
+			assert.ElementsMatch(t, want, got)
+		})
+
+		t.Run(tt.name+" be", func(t *testing.T) {
+			code := make([]byte, 0, len(tt.words)*4)
+			for _, w := range tt.words {
+				code = binary.BigEndian.AppendUint32(code, w)
+			}
+			got := extractPpc64(code, 0x10086f60, nil)
+			assert.ElementsMatch(t, want, got)
+		})
+	}
+}
+
+func TestExtractPpc64TOC(t *testing.T) {
+	// main.main of a -buildmode=pie binary, the TOC is 0x1b8898
+	words := []uint32{
+		0x3c4c0010, // ADDIS R12,$16,R2
+		0x3842dfa8, // ADD R2,$-8280,R2
+		0x3c62fff0, // ADDIS R2,$-16,R3
+		0x38637778, // ADD R3,$30584,R3
+		0x38800002, // MOVD $2,R4
+	}
+
+	got := extractPpc64le(wordsToCode(words...), 0xba8f0, nil)
+	assert.ElementsMatch(t, []PossibleStr{{Addr: 0xc0010, Size: 2}}, got)
+
+	// without the prologue the base in R2 is unknown
+	got = extractPpc64le(wordsToCode(words[2:]...), 0xba8f8, nil)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,113 @@
+package disasm
+
+import (
+	"golang.org/x/arch/riscv64/riscv64asm"
+)
+
+type riscv64PosInst struct {
+	pc   uint64
+	inst riscv64asm.Inst
+}
+
+type riscv64Pattern = pattern[riscv64PosInst]
+
+// riscv64GetAddr resolves an AUIPC+ADDI pair into the absolute address it
+// materializes, and returns the register holding it.
+func riscv64GetAddr(auipc, addi riscv64PosInst) (uint64, riscv64asm.Reg, bool) {
+	if auipc.inst.Op != riscv64asm.AUIPC || addi.inst.Op != riscv64asm.ADDI {
+		return 0, 0, false
+	}
+
+	reg, ok := auipc.inst.Args[0].(riscv64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	hi, ok := auipc.inst.Args[1].(riscv64asm.Uimm)
+	if !ok {
+		return 0, 0, false
+	}
+
+	dst, ok := addi.inst.Args[0].(riscv64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := addi.inst.Args[1].(riscv64asm.Reg)
+	if !ok || src != reg {
+		return 0, 0, false
+	}
+	lo, ok := addi.inst.Args[2].(riscv64asm.Simm)
+	if !ok {
+		return 0, 0, false
+	}
+
+	// the upper immediate is sign extended after shifting on rv64
+	off := int64(int32(hi.Imm<<12)) + int64(lo.Imm)
+	return uint64(int64(auipc.pc) + off), dst, true
+}
+
+// riscv64GetLen matches ADDI reg, ZERO, imm, the expansion of MOV $imm, reg.
+func riscv64GetLen(inst riscv64asm.Inst) (uint64, riscv64asm.Reg, bool) {
+	if inst.Op != riscv64asm.ADDI {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(riscv64asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	src, ok := inst.Args[1].(riscv64asm.Reg)
+	if !ok || src != riscv64asm.X0 {
+		return 0, 0, false
+	}
+	imm, ok := inst.Args[2].(riscv64asm.Simm)
+	if !ok || imm.Imm <= 0 {
+		return 0, 0, false
+	}
+	return uint64(imm.Imm), reg, true
+}
+
+var riscv64Patterns = []riscv64Pattern{
+	{
+		windowSize: 3,
+		// 1.
+		//
+		//	main.go:74            0x8c71c                 00032517                AUIPC $50, X10
+		//	main.go:74            0x8c720                 c2c50513                ADDI $-980, X10, X10
+		//	main.go:74            0x8c724                 01e00593                ADDI $30, X0, X11
+		matchFunc: func(insts []riscv64PosInst, _ addrReader) *PossibleStr {
+			addr, addrReg, ok := riscv64GetAddr(insts[0], insts[1])
+			if !ok {
+				return nil
+			}
+			size, lenReg, ok := riscv64GetLen(insts[2].inst)
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 3,
+		// 2. the length is materialized before the pointer
+		//
+		//	main.go:74            0x8c71c                 01e00593                ADDI $30, X0, X11
+		//	main.go:74            0x8c720                 00032517                AUIPC $50, X10
+		//	main.go:74            0x8c724                 c2c50513                ADDI $-980, X10, X10
+		matchFunc: func(insts []riscv64PosInst, _ addrReader) *PossibleStr {
+			size, lenReg, ok := riscv64GetLen(insts[0].inst)
+			if !ok {
+				return nil
+			}
+			addr, addrReg, ok := riscv64GetAddr(insts[1], insts[2])
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
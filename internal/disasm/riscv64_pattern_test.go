@@ -0,0 +1,45 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractRiscv64(t *testing.T) {
+	const (
+		auipc = 0x00032517 // AUIPC $50, X10
+		addi  = 0xc2c50513 // ADDI $-980, X10, X10
+		li    = 0x01e00593 // ADDI $30, X0, X11
+		liA0  = 0x01e00513 // ADDI $30, X0, X10
+	)
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "address then length",
+			code: wordsToCode(auipc, addi, li),
+			want: []PossibleStr{{Addr: 0xbe348, Size: 30}},
+		},
+		{
+			name: "length then address",
+			code: wordsToCode(li, auipc, addi),
+			want: []PossibleStr{{Addr: 0xbe34c, Size: 30}},
+		},
+		{
+			name: "length overwrites address",
+			code: wordsToCode(auipc, addi, liA0),
+			want: []PossibleStr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractRiscv64(tt.code, 0x8c71c, nil)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package disasm
+
+import (
+	"golang.org/x/arch/s390x/s390xasm"
+)
+
+type s390xPosInst struct {
+	pc   uint64
+	inst s390xasm.Inst
+}
+
+type s390xPattern = pattern[s390xPosInst]
+
+// s390xGetAddr resolves LARL reg, off into the absolute address it loads.
+func s390xGetAddr(pos s390xPosInst) (uint64, s390xasm.Reg, bool) {
+	if pos.inst.Op != s390xasm.LARL {
+		return 0, 0, false
+	}
+	reg, ok := pos.inst.Args[0].(s390xasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	off, ok := pos.inst.Args[1].(s390xasm.RegIm32)
+	if !ok {
+		return 0, 0, false
+	}
+	// the offset is counted in halfwords
+	return uint64(int64(pos.pc) + 2*int64(int32(off))), reg, true
+}
+
+// s390xGetLen matches the immediate loads MOVD $imm, reg expands to.
+func s390xGetLen(inst s390xasm.Inst) (uint64, s390xasm.Reg, bool) {
+	reg, ok := inst.Args[0].(s390xasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+
+	var size int64
+	switch imm := inst.Args[1].(type) {
+	case s390xasm.Sign16:
+		if inst.Op != s390xasm.LGHI {
+			return 0, 0, false
+		}
+		size = int64(imm)
+	case s390xasm.Sign32:
+		if inst.Op != s390xasm.LGFI {
+			return 0, 0, false
+		}
+		size = int64(imm)
+	case s390xasm.Imm:
+		if inst.Op != s390xasm.LLILF {
+			return 0, 0, false
+		}
+		size = int64(imm)
+	default:
+		return 0, 0, false
+	}
+
+	if size <= 0 {
+		return 0, 0, false
+	}
+	return uint64(size), reg, true
+}
+
+// s390xGetStackStore matches STG reg, off(R15) and returns off and reg.
+func s390xGetStackStore(inst s390xasm.Inst) (int32, s390xasm.Reg, bool) {
+	if inst.Op != s390xasm.STG {
+		return 0, 0, false
+	}
+	reg, ok := inst.Args[0].(s390xasm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	disp, ok := inst.Args[1].(s390xasm.Disp20)
+	if !ok {
+		return 0, 0, false
+	}
+	index, ok := inst.Args[2].(s390xasm.Index)
+	if !ok || index != s390xasm.X0 {
+		return 0, 0, false
+	}
+	base, ok := inst.Args[3].(s390xasm.Base)
+	if !ok || base != s390xasm.B15 {
+		return 0, 0, false
+	}
+	return int32(disp<<12) >> 12, reg, true
+}
+
+var s390xPatterns = []s390xPattern{
+	{
+		windowSize: 4,
+		// 1. arguments passed on stack
+		//
+		//	main.go:74            0x9a3d6                 c01000032a1d            MOVD 69129(PC), R1
+		//	main.go:74            0x9a3dc                 e310f0080024            MOVD R1, 8(R15)
+		//	main.go:74            0x9a3e2                 a719001e                MOVB $30, R1
+		//	main.go:74            0x9a3e6                 e310f0100024            MOVD R1, 16(R15)
+		matchFunc: func(insts []s390xPosInst, _ addrReader) *PossibleStr {
+			addr, addrReg, ok := s390xGetAddr(insts[0])
+			if !ok {
+				return nil
+			}
+			ptrOff, reg, ok := s390xGetStackStore(insts[1].inst)
+			if !ok || reg != addrReg {
+				return nil
+			}
+
+			size, lenReg, ok := s390xGetLen(insts[2].inst)
+			if !ok {
+				return nil
+			}
+			lenOff, reg, ok := s390xGetStackStore(insts[3].inst)
+			if !ok || reg != lenReg || lenOff != ptrOff+8 {
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 2,
+		// 2. both halves kept in registers
+		//
+		//	main.go:74            0x9a3d6                 c02000032a1d            MOVD 69129(PC), R2
+		//	main.go:74            0x9a3dc                 a739001e                MOVB $30, R3
+		matchFunc: func(insts []s390xPosInst, _ addrReader) *PossibleStr {
+			addr, addrReg, ok := s390xGetAddr(insts[0])
+			if !ok {
+				return nil
+			}
+			size, lenReg, ok := s390xGetLen(insts[1].inst)
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+	{
+		windowSize: 2,
+		// 3. same as 2, the length is materialized before the pointer
+		//
+		//	main.go:74            0x9a3d6                 a739001e                MOVB $30, R3
+		//	main.go:74            0x9a3da                 c02000032a1d            MOVD 69129(PC), R2
+		matchFunc: func(insts []s390xPosInst, _ addrReader) *PossibleStr {
+			size, lenReg, ok := s390xGetLen(insts[0].inst)
+			if !ok {
+				return nil
+			}
+			addr, addrReg, ok := s390xGetAddr(insts[1])
+			if !ok || lenReg == addrReg {
+				return nil
+			}
+			return &PossibleStr{
+				Addr: addr,
+				Size: size,
+			}
+		},
+	},
+}
@@ -0,0 +1,59 @@
+package disasm
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractS390x(t *testing.T) {
+	var (
+		larlR1   = []byte{0xc0, 0x10, 0x00, 0x03, 0x2a, 0x1d} // MOVD 69129(PC), R1
+		larlR2   = []byte{0xc0, 0x20, 0x00, 0x03, 0x2a, 0x1d} // MOVD 69129(PC), R2
+		stgPtr   = []byte{0xe3, 0x10, 0xf0, 0x08, 0x00, 0x24} // MOVD R1, 8(R15)
+		lghiR1   = []byte{0xa7, 0x19, 0x00, 0x1e}             // MOVB $30, R1
+		stgLen   = []byte{0xe3, 0x10, 0xf0, 0x10, 0x00, 0x24} // MOVD R1, 16(R15)
+		stgWrong = []byte{0xe3, 0x10, 0xf0, 0x18, 0x00, 0x24} // MOVD R1, 24(R15)
+		lghiR3   = []byte{0xa7, 0x39, 0x00, 0x1e}             // MOVB $30, R3
+	)
+
+	tests := []struct {
+		name string
+		code []byte
+		want []PossibleStr
+	}{
+		{
+			name: "stack args",
+			code: slices.Concat(larlR1, stgPtr, lghiR1, stgLen),
+			want: []PossibleStr{{Addr: 0xff810, Size: 30}},
+		},
+		{
+			name: "mismatched stack slots",
+			code: slices.Concat(larlR1, stgPtr, lghiR1, stgWrong),
+			want: []PossibleStr{},
+		},
+		{
+			name: "registers",
+			code: slices.Concat(larlR2, lghiR3),
+			want: []PossibleStr{{Addr: 0xff810, Size: 30}},
+		},
+		{
+			name: "length then address",
+			code: slices.Concat(lghiR3, larlR2),
+			want: []PossibleStr{{Addr: 0xff814, Size: 30}},
+		},
+		{
+			name: "truncated",
+			code: larlR2[:4],
+			want: []PossibleStr{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractS390x(tt.code, 0x9a3d6, nil)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package disasm
 
 import (
+	"encoding/binary"
+
 	"golang.org/x/arch/x86/x86asm"
 )
 
@@ -9,10 +11,7 @@ type x86PosInst struct {
 	inst x86asm.Inst
 }
 
-type x86Pattern struct {
-	windowSize int
-	matchFunc  func([]x86PosInst) *PossibleStr
-}
+type x86Pattern = pattern[x86PosInst]
 
 func x86CountNotNilArgs(args x86asm.Args) int {
 	cnt := 0
@@ -45,6 +44,26 @@ func x86GetMovImm(inst x86asm.Inst) (uint64, bool) {
 	return secondImmVal, true
 }
 
+// x86GetRIPLoad matches a 64-bit MOV from a RIP-relative slot into a register,
+// returns the absolute address of the slot and the target register.
+func x86GetRIPLoad(pos x86PosInst) (uint64, x86asm.Reg, bool) {
+	inst := pos.inst
+	if inst.Op != x86asm.MOV || x86CountNotNilArgs(inst.Args) != 2 || inst.DataSize != 64 {
+		return 0, 0, false
+	}
+
+	reg, ok := inst.Args[0].(x86asm.Reg)
+	if !ok {
+		return 0, 0, false
+	}
+	mem, ok := inst.Args[1].(x86asm.Mem)
+	if !ok || mem.Base != x86asm.RIP {
+		return 0, 0, false
+	}
+
+	return pos.pc + uint64(inst.Len) + uint64(mem.Disp), reg, true
+}
+
 // currently only two x86Pattern get supported.
 var x86Patterns = []x86Pattern{
 	{
@@ -57,7 +76,7 @@ var x86Patterns = []x86Pattern{
 		//	main.go:74            0x48067f                90                      NOPL
 		//	main.go:74            0x480680                e87b2cfbff              CALL runtime.printstring(SB)
 		//	main.go:74            0x480685                e85624fbff              CALL runtime.printunlock(SB)
-		matchFunc: func(insts []x86PosInst) *PossibleStr {
+		matchFunc: func(insts []x86PosInst, _ addrReader) *PossibleStr {
 			first := insts[0]
 			firstInst := first.inst
 			if x86CountNotNilArgs(firstInst.Args) != 2 {
@@ -89,7 +108,7 @@ var x86Patterns = []x86Pattern{
 	},
 	{
 		windowSize: 2,
-		// 2. todo: implement this, this is common for read-write data
+		// 2. string header stored in read-write data, common for package level string variables
 		//
 		//	main.go:79            0x4806ae                e8cd23fbff              CALL runtime.printlock(SB)
 		//	main.go:79            0x4806b3                488b0536600a00          MOVQ main.GlobalString(SB), AX
@@ -97,29 +116,43 @@ var x86Patterns = []x86Pattern{
 		//	main.go:79            0x4806c1                e83a2cfbff              CALL runtime.printstring(SB)
 		//	main.go:79            0x4806c6                e8f525fbff              CALL runtime.printnl(SB)
 		//	main.go:79            0x4806cb                e81024fbff              CALL runtime.printunlock(SB)
-		// matchFunc: func(insts []x86PosInst) *PossibleStr {
-		// 	first := insts[0]
-		// 	firstInst := first.inst
-		// 	if firstInst.Op != x86asm.MOV {
-		// 		return nil
-		// 	}
-		// 	if x86CountNotNilArgs(firstInst.Args) != 2 {
-		// 		return nil
-		// 	}
-
-		// 	second := insts[1]
-		// 	secondInst := second.inst
-		// 	if secondInst.Op != x86asm.MOV {
-		// 		return nil
-		// 	}
-		// 	if x86CountNotNilArgs(secondInst.Args) != 2 {
-		// 		return nil
-		// 	}
-
-		// 	return nil
-		// },
-		matchFunc: func(_ []x86PosInst) *PossibleStr {
-			return nil
+		matchFunc: func(insts []x86PosInst, read addrReader) *PossibleStr {
+			firstAddr, firstReg, ok := x86GetRIPLoad(insts[0])
+			if !ok {
+				return nil
+			}
+			secondAddr, secondReg, ok := x86GetRIPLoad(insts[1])
+			if !ok || firstReg == secondReg {
+				return nil
+			}
+
+			// the header is {ptr, len}, loads may come in either order
+			var headerAddr uint64
+			switch {
+			case secondAddr == firstAddr+8:
+				headerAddr = firstAddr
+			case firstAddr == secondAddr+8:
+				headerAddr = secondAddr
+			default:
+				return nil
+			}
+
+			header, err := read(headerAddr, 16)
+			if err != nil || len(header) < 16 {
+				return nil
+			}
+
+			ptr := binary.LittleEndian.Uint64(header[:8])
+			size := binary.LittleEndian.Uint64(header[8:])
+			if ptr == 0 || size == 0 {
+				// bss or relocated by the loader, nothing to read from the file
+				return nil
+			}
+
+			return &PossibleStr{
+				Addr: ptr,
+				Size: size,
+			}
 		},
 	},
 }
@@ -0,0 +1,43 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractAmd64GlobalString(t *testing.T) {
+	// MOVQ 0x2000(SB), AX; MOVQ 0x2008(SB), BX with pc starting at 0x1000
+	code := []byte{
+		0x48, 0x8b, 0x05, 0xf9, 0x0f, 0x00, 0x00,
+		0x48, 0x8b, 0x1d, 0xfa, 0x0f, 0x00, 0x00,
+	}
+
+	header := binary.LittleEndian.AppendUint64(nil, 0x3000)
+	header = binary.LittleEndian.AppendUint64(header, 0x42)
+
+	t.Run("header in data", func(t *testing.T) {
+		got := extractAmd64(code, 0x1000, func(addr, size uint64) ([]byte, error) {
+			assert.Equal(t, uint64(0x2000), addr)
+			assert.Equal(t, uint64(16), size)
+			return header, nil
+		})
+		assert.Equal(t, []PossibleStr{{Addr: 0x3000, Size: 0x42}}, got)
+	})
+
+	t.Run("header not in file", func(t *testing.T) {
+		got := extractAmd64(code, 0x1000, func(_, _ uint64) ([]byte, error) {
+			return nil, errors.New("not in file")
+		})
+		assert.Empty(t, got)
+	})
+
+	t.Run("zeroed header", func(t *testing.T) {
+		got := extractAmd64(code, 0x1000, func(_, size uint64) ([]byte, error) {
+			return make([]byte, size), nil
+		})
+		assert.Empty(t, got)
+	})
+}
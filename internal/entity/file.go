@@ -10,7 +10,7 @@ type File struct {
 func (f *File) FullSize() uint64 {
 	size := uint64(0)
 	for _, fn := range f.Functions {
-		size += fn.Size()
+		size += fn.FullSize()
 	}
 	return size
 }
@@ -18,7 +18,9 @@ func (f *File) FullSize() uint64 {
 func (f *File) PclnSize() uint64 {
 	size := uint64(0)
 	for _, fn := range f.Functions {
-		size += fn.PclnSize.Size()
+		for w := range fn.All {
+			size += w.PclnSize.Size()
+		}
 	}
 	return size
 }
@@ -92,3 +92,21 @@ func TestFile_MarshalJSON(t *testing.T) {
 		assert.JSONEq(t, expected, string(data))
 	})
 }
+
+func TestFile_SizeWithWrappers(t *testing.T) {
+	file := &entity.File{
+		Functions: []*entity.Function{
+			{CodeSize: 10, PclnSize: entity.PclnSymbolSize{Header: 1}, Wrappers: []*entity.Function{
+				{CodeSize: 5, PclnSize: entity.PclnSymbolSize{Header: 2}, Wrappers: []*entity.Function{
+					{CodeSize: 3},
+				}},
+			}},
+			{CodeSize: 20},
+		},
+	}
+
+	assert.Equal(t, uint64(11), file.Functions[0].Size())
+	assert.Equal(t, uint64(21), file.Functions[0].FullSize())
+	assert.Equal(t, uint64(41), file.FullSize())
+	assert.Equal(t, uint64(3), file.PclnSize())
+}
@@ -16,6 +16,15 @@ type Function struct {
 
 	PclnSize PclnSymbolSize `json:"pcln_size"`
 
+	// Calls are the entry addresses of the functions called directly, sorted,
+	// only filled with the call graph.
+	Calls []uint64 `json:"calls,omitempty"`
+
+	// Wrappers are the closures, method values, go and defer wrappers and
+	// autogenerated method wrappers folded into the function, sorted by address,
+	// only filled with the folding.
+	Wrappers []*Function `json:"wrappers,omitempty"`
+
 	disasm AddrSpace
 	pkg    *Package
 }
@@ -24,6 +33,33 @@ func (f *Function) Init() {
 	f.disasm = make(AddrSpace)
 }
 
+// Size is the size of the function itself, without the wrappers.
 func (f *Function) Size() uint64 {
 	return f.CodeSize + f.PclnSize.Size()
 }
+
+// FullSize is the size of the function along with the wrappers folded into it.
+func (f *Function) FullSize() uint64 {
+	size := uint64(0)
+	for fn := range f.All {
+		size += fn.Size()
+	}
+	return size
+}
+
+// All yields f and the wrappers folded into it, recursively.
+func (f *Function) All(yield func(*Function) bool) {
+	f.all(yield)
+}
+
+func (f *Function) all(yield func(*Function) bool) bool {
+	if !yield(f) {
+		return false
+	}
+	for _, w := range f.Wrappers {
+		if !w.all(yield) {
+			return false
+		}
+	}
+	return true
+}
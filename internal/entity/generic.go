@@ -0,0 +1,30 @@
+package entity
+
+// Generic is a generic function or method of a package along with its
+// instantiations found in the binary.
+type Generic struct {
+	Package string `json:"package"`
+	// Name is the function name without the type arguments,
+	// methods are qualified by the receiver, e.g. (*List).Push.
+	Name string `json:"name"`
+
+	// Size is the total size of the instantiations.
+	Size uint64 `json:"size"`
+	// Instances are sorted by size, the largest first.
+	Instances []*Instance `json:"instances"`
+}
+
+// Instance is an instantiation of a generic function.
+type Instance struct {
+	// TypeArgs are the type arguments as the linker names them, e.g. go.shape.int,string.
+	TypeArgs string `json:"type_args"`
+	// Shape is set for the instantiations shared by the types of the same
+	// underlying shape, the ones taking a dictionary.
+	Shape bool `json:"shape"`
+
+	Addr uint64 `json:"addr"`
+	Size uint64 `json:"size"`
+}
+
+// ShapePrefix is the prefix of the shape types in the instantiation names.
+const ShapePrefix = "go.shape."
@@ -0,0 +1,25 @@
+//go:build js && wasm
+
+package entity
+
+import (
+	"github.com/samber/lo"
+)
+
+func (g *Generic) MarshalJavaScript() any {
+	return map[string]any{
+		"package":   g.Package,
+		"name":      g.Name,
+		"size":      g.Size,
+		"instances": lo.Map(g.Instances, func(i *Instance, _ int) any { return i.MarshalJavaScript() }),
+	}
+}
+
+func (i *Instance) MarshalJavaScript() any {
+	return map[string]any{
+		"type_args": i.TypeArgs,
+		"shape":     i.Shape,
+		"addr":      i.Addr,
+		"size":      i.Size,
+	}
+}
@@ -0,0 +1,15 @@
+package entity
+
+// Inline is the code of a function inlined into the functions of another package.
+type Inline struct {
+	// Package is the package of the functions the code is inlined into.
+	Package string `json:"package"`
+	// Function is the inlined function, qualified by its package.
+	Function string `json:"function"`
+	// Origin is the package of the inlined function.
+	Origin string `json:"origin"`
+
+	// Calls is the count of the inlined calls.
+	Calls int    `json:"calls"`
+	Size  uint64 `json:"size"`
+}
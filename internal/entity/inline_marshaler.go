@@ -0,0 +1,13 @@
+//go:build js && wasm
+
+package entity
+
+func (i *Inline) MarshalJavaScript() any {
+	return map[string]any{
+		"package":  i.Package,
+		"function": i.Function,
+		"origin":   i.Origin,
+		"calls":    i.Calls,
+		"size":     i.Size,
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "strings"
+
+// Module is a Go module of the binary along with the packages it owns.
+// The std and unknown pseudo modules hold the standard library and the
+// packages no module owns, such as the generated ones.
+type Module struct {
+	Path string `json:"path"`
+	// Version is the version of the replacement if the module is replaced,
+	// it is empty for a local replacement.
+	Version string `json:"version"`
+	// Replace is the path of the replacement module.
+	Replace string `json:"replace,omitempty"`
+
+	Type PackageType `json:"type"`
+
+	// Packages are the names of the owned packages, sorted.
+	Packages []string `json:"packages"`
+	Size     uint64   `json:"size"`
+}
+
+const (
+	ModulePathStd     = "std"
+	ModulePathUnknown = "unknown"
+)
+
+// VersionString is the version as `go version -m` shows it, preceded by the
+// replacement path if the module is replaced.
+func (m *Module) VersionString() string {
+	if m.Replace == "" {
+		return m.Version
+	}
+	return strings.TrimSpace("=> " + m.Replace + " " + m.Version)
+}
+
+// OwnSize is the size of the package without its sub packages.
+func (p *Package) OwnSize() uint64 {
+	size := p.Size
+	for _, sp := range p.SubPackages {
+		// overlapped symbols can make the sub packages larger than the parent
+		if sp.Size >= size {
+			return 0
+		}
+		size -= sp.Size
+	}
+	return size
+}
@@ -0,0 +1,21 @@
+//go:build js && wasm
+
+package entity
+
+import (
+	"github.com/samber/lo"
+)
+
+func (m *Module) MarshalJavaScript() any {
+	ret := map[string]any{
+		"path":     m.Path,
+		"version":  m.Version,
+		"type":     m.Type,
+		"packages": lo.Map(m.Packages, func(p string, _ int) any { return p }),
+		"size":     m.Size,
+	}
+	if m.Replace != "" {
+		ret["replace"] = m.Replace
+	}
+	return ret
+}
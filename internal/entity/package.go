@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"sync"
 
@@ -41,6 +42,10 @@ type Package struct {
 	Symbols []*Symbol `json:"symbols"`
 
 	ImportedBy []string `json:"importedBy,omitempty"`
+	// UsedBy are the packages calling the package directly, only filled with the call graph.
+	UsedBy []string `json:"usedBy,omitempty"`
+	// Retained is the size saved without the package, only filled with the imports.
+	Retained uint64 `json:"retained,omitzero"`
 
 	filesCache map[string]*File
 	funcsCache map[string]*Function
@@ -66,24 +71,18 @@ func NewPackage() *Package {
 	return p
 }
 
-func NewPackageWithGorePackage(gp *gore.Package, name string, typ PackageType, pclntab *gosym.Table, getCodeSize func(function *gore.Function) uint64, isWasm bool) *Package {
+func NewPackageWithGorePackage(gp *gore.Package, name string, typ PackageType, pclntab *gosym.Table, getCodeSize func(function *gore.Function) uint64, getPclnSize func(function *gore.Function) PclnSymbolSize) *Package {
 	p := NewPackage()
 	p.Name = utils.Deduplicate(name)
 	p.Type = typ
 	p.loaded = true
 
 	getFunction := func(f *gore.Function) *Function {
-		// fixme: pclntab size for wasm currently broken
-		pclnSize := PclnSymbolSize{}
-		if !isWasm {
-			pclnSize = NewPclnSymbolSize(f.Func)
-		}
-
 		return &Function{
 			Name:     utils.Deduplicate(f.Name),
 			Addr:     f.Offset,
 			CodeSize: getCodeSize(f),
-			PclnSize: pclnSize,
+			PclnSize: getPclnSize(f),
 			Type:     FuncTypeFunction,
 			disasm:   AddrSpace{},
 			pkg:      p,
@@ -189,16 +188,35 @@ func (p *Package) Merge(rp *Package) {
 	maps.Copy(p.SubPackages, rp.SubPackages)
 }
 
+// Functions yields the functions of the files, along with the wrappers folded into them.
 func (p *Package) Functions(yield func(*Function) bool) {
 	for _, f := range p.Files {
 		for _, fn := range f.Functions {
-			if !yield(fn) {
+			if !fn.all(yield) {
 				return
 			}
 		}
 	}
 }
 
+// RemoveFunctions removes fns from the files of p, dropping the files left empty.
+func (p *Package) RemoveFunctions(fns utils.Set[*Function]) {
+	p.Files = slices.DeleteFunc(p.Files, func(f *File) bool {
+		n := len(f.Functions)
+		f.Functions = slices.DeleteFunc(f.Functions, fns.Contains)
+		if n == 0 || len(f.Functions) > 0 {
+			return false
+		}
+		delete(p.filesCache, f.FilePath)
+		return true
+	})
+	for name, fn := range p.funcsCache {
+		if fns.Contains(fn) {
+			delete(p.funcsCache, name)
+		}
+	}
+}
+
 func (p *Package) GetDisasmAddrSpace() AddrSpace {
 	spaces := make([]AddrSpace, 0)
 	for f := range p.Functions {
@@ -22,7 +22,7 @@ func (p *Package) MarshalJavaScript() any {
 	files = lo.Map(p.Files, func(f *File, _ int) any { return f.MarshalJavaScript() })
 	subs := p.SubPackages.MarshalJavaScript()
 
-	return map[string]any{
+	ret := map[string]any{
 		"name":        p.Name,
 		"type":        p.Type,
 		"size":        p.Size,
@@ -30,4 +30,15 @@ func (p *Package) MarshalJavaScript() any {
 		"subPackages": subs,
 		"files":       files,
 	}
+	// omitted like the empty fields of the json output
+	if len(p.ImportedBy) > 0 {
+		ret["importedBy"] = lo.Map(p.ImportedBy, func(s string, _ int) any { return s })
+	}
+	if len(p.UsedBy) > 0 {
+		ret["usedBy"] = lo.Map(p.UsedBy, func(s string, _ int) any { return s })
+	}
+	if p.Retained > 0 {
+		ret["retained"] = p.Retained
+	}
+	return ret
 }
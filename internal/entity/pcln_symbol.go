@@ -1,6 +1,10 @@
 package entity
 
-import "github.com/ZxillyFork/gosym"
+import (
+	"encoding/binary"
+
+	"github.com/ZxillyFork/gosym"
+)
 
 // PclnSymbolSize represents a pcln symbol sizes
 type PclnSymbolSize struct {
@@ -39,6 +43,99 @@ func NewPclnSymbolSize(s *gosym.Func) PclnSymbolSize {
 	}
 }
 
+// pclntab magics of the versions with a pctab offset in the header.
+const (
+	go116magic = 0xfffffffa
+	go118magic = 0xfffffff0
+	go120magic = 0xfffffff1
+)
+
+// wasmPctab returns the pc-value tables of the little endian pclntab t, the
+// table offsets of a function are relative to it.
+func wasmPctab(t *gosym.LineTable) []byte {
+	d := t.Data
+	if len(d) < 8 {
+		return nil
+	}
+	ptrSize := int(d[7])
+	word := func(n int) (uint64, bool) {
+		off := 8 + n*ptrSize
+		switch {
+		case ptrSize == 8 && len(d) >= off+8:
+			return binary.LittleEndian.Uint64(d[off:]), true
+		case ptrSize == 4 && len(d) >= off+4:
+			return uint64(binary.LittleEndian.Uint32(d[off:])), true
+		}
+		return 0, false
+	}
+
+	var off uint64
+	var ok bool
+	switch binary.LittleEndian.Uint32(d) {
+	case go118magic, go120magic:
+		off, ok = word(6)
+	case go116magic:
+		off, ok = word(5)
+	default:
+		// Go 1.2 offsets are relative to the pclntab
+		return d
+	}
+	if !ok || off > uint64(len(d)) {
+		return nil
+	}
+	return d[off:]
+}
+
+// pcvalueEnd returns the pc, relative to the entry, where the pc-value table
+// at off of tab ends, the value delta 0 after the first entry terminates it.
+func pcvalueEnd(tab []byte, off uint32, quantum uint64) uint64 {
+	if off == 0 || int(off) >= len(tab) {
+		return 0
+	}
+	p := tab[off:]
+
+	var pc uint64
+	for first := true; ; first = false {
+		vd, n := binary.Uvarint(p)
+		if n <= 0 || (vd == 0 && !first) {
+			return pc
+		}
+		p = p[n:]
+		pcd, n := binary.Uvarint(p)
+		if n <= 0 {
+			return pc
+		}
+		p = p[n:]
+		pc += pcd * quantum
+	}
+}
+
+// NewWasmPclnSymbolSize returns the pcln sizes of the wasm function s. The pc
+// of wasm is PC_F<<16 | PC_B, the next entry of the pclntab is far beyond the
+// last block of s, so the tables are walked up to the end of the pcsp table.
+// meq125 reports the pclntab of Go 1.25+ holding PC_F alone. A pc table shared
+// by several functions counts for each of them, and the pcHeader for none, the
+// sum over the functions is bounded by the pclntab when it is accounted.
+func NewWasmPclnSymbolSize(s *gosym.Func, meq125 bool) PclnSymbolSize {
+	f := *s
+	if meq125 {
+		f.Entry <<= 16
+	}
+
+	tab := wasmPctab(f.LineTable)
+	quantum := uint64(1)
+	if len(f.LineTable.Data) > 6 {
+		quantum = uint64(f.LineTable.Data[6])
+	}
+	off := f.OffPCSP
+	if off == 0 {
+		off = f.OffPCLn
+	}
+	f.End = f.Entry + pcvalueEnd(tab, off, quantum)
+
+	return NewPclnSymbolSize(&f)
+}
+
 func NewEmptyPclnSymbolSize() PclnSymbolSize {
 	return PclnSymbolSize{
 		PCData: make(map[string]int),
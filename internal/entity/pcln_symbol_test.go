@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ZxillyFork/gosym"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPcvalueEnd(t *testing.T) {
+	// the pclntab offset 0 means no table
+	tab := []byte{0xff, 0x02, 0x03, 0x04, 0x05, 0x00, 0x00, 0x02, 0x00}
+
+	assert.Equal(t, uint64(8), pcvalueEnd(tab, 1, 1))
+	assert.Equal(t, uint64(16), pcvalueEnd(tab, 1, 2))
+	// the value delta 0 of the first entry does not end the table
+	assert.Equal(t, uint64(2), pcvalueEnd(tab, 6, 1))
+	assert.Zero(t, pcvalueEnd(tab, 0, 1))
+	assert.Zero(t, pcvalueEnd(tab, uint32(len(tab)), 1))
+	// a truncated table ends at the last full entry
+	assert.Equal(t, uint64(3), pcvalueEnd(tab[:4], 1, 1))
+}
+
+func TestWasmPctab(t *testing.T) {
+	header := func(magic uint32, word int) []byte {
+		d := make([]byte, 8+8*8, 8+8*8+1)
+		binary.LittleEndian.PutUint32(d, magic)
+		d[6], d[7] = 1, 8
+		binary.LittleEndian.PutUint64(d[8+word*8:], uint64(len(d)))
+		return append(d, 0xaa)
+	}
+
+	assert.Equal(t, []byte{0xaa}, wasmPctab(&gosym.LineTable{Data: header(go120magic, 6)}))
+	assert.Equal(t, []byte{0xaa}, wasmPctab(&gosym.LineTable{Data: header(go118magic, 6)}))
+	assert.Equal(t, []byte{0xaa}, wasmPctab(&gosym.LineTable{Data: header(go116magic, 5)}))
+
+	// Go 1.2 offsets are relative to the pclntab
+	d := header(0xfffffffb, 0)
+	assert.Equal(t, d, wasmPctab(&gosym.LineTable{Data: d}))
+
+	bad := header(go120magic, 6)
+	binary.LittleEndian.PutUint64(bad[8+6*8:], uint64(len(bad)+1))
+	assert.Nil(t, wasmPctab(&gosym.LineTable{Data: bad}))
+	assert.Nil(t, wasmPctab(&gosym.LineTable{Data: []byte{0xf1}}))
+}
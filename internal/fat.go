@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/wrapper"
+)
+
+var (
+	ErrNotFat  = errors.New("not a universal binary")
+	ErrFatArch = errors.New("universal binary")
+)
+
+// fatArches lists the architectures of slices for the error messages.
+func fatArches(slices []wrapper.FatSlice) string {
+	arches := make([]string, len(slices))
+	for i, s := range slices {
+		arches[i] = s.Arch
+	}
+	return strings.Join(arches, ", ")
+}
+
+// pickSlice returns the slice of arch if reader is a universal binary, the only
+// slice is picked without arch. Other binaries are returned as is.
+func pickSlice(reader io.ReaderAt, size uint64, arch string) (io.ReaderAt, uint64, error) {
+	slices, ok := wrapper.FatSlices(reader)
+	if !ok {
+		return reader, size, nil
+	}
+
+	for _, s := range slices {
+		if s.Arch == arch || (arch == "" && len(slices) == 1) {
+			return io.NewSectionReader(reader, int64(s.Offset), int64(s.Size)), s.Size, nil
+		}
+	}
+	if arch == "" {
+		return nil, 0, fmt.Errorf("%w of %s, choose an architecture", ErrFatArch, fatArches(slices))
+	}
+	return nil, 0, fmt.Errorf("%w of %s, no %s slice", ErrFatArch, fatArches(slices), arch)
+}
+
+// AnalyzeFat analyzes every slice of the universal binary in reader, the Arch
+// of options is ignored. It returns ErrNotFat for other binaries.
+func AnalyzeFat(ctx context.Context, name string, reader io.ReaderAt, size uint64, options Options) (*result.Fat, error) {
+	slices, ok := wrapper.FatSlices(reader)
+	if !ok {
+		return nil, ErrNotFat
+	}
+
+	ret := &result.Fat{
+		Name:   filepath.Base(name),
+		Size:   size,
+		Slices: make([]*result.Slice, 0, len(slices)),
+	}
+	for _, s := range slices {
+		options.Arch = s.Arch
+		r, err := AnalyzeContext(ctx, name, io.NewSectionReader(reader, int64(s.Offset), int64(s.Size)), s.Size, options)
+		if err != nil {
+			return nil, fmt.Errorf("analyze %s slice: %w", s.Arch, err)
+		}
+		ret.Slices = append(ret.Slices, &result.Slice{Arch: s.Arch, Offset: s.Offset, Size: s.Size, Result: r})
+	}
+	return ret, nil
+}
@@ -0,0 +1,64 @@
+package knowninfo
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// UpdateUsedBy derives the package uses graph from the calls of the functions,
+// a package uses another one if any of its functions calls one of the other.
+func (m *Dependencies) UpdateUsedBy() {
+	slog.Info("Building package uses graph...")
+
+	owner := make(map[uint64]string)
+	_ = m.Trie.Walk(func(_ string, p *entity.Package) error {
+		for fn := range p.Functions {
+			owner[fn.Addr] = p.Name
+		}
+		return nil
+	})
+
+	usedBy := make(map[string]utils.Set[string])
+	_ = m.Trie.Walk(func(_ string, p *entity.Package) error {
+		for fn := range p.Functions {
+			for _, addr := range fn.Calls {
+				callee, ok := owner[addr]
+				if !ok || callee == p.Name {
+					continue
+				}
+				if usedBy[callee] == nil {
+					usedBy[callee] = utils.NewSet[string]()
+				}
+				usedBy[callee].Add(p.Name)
+			}
+		}
+		return nil
+	})
+
+	_ = m.Trie.Walk(func(_ string, p *entity.Package) error {
+		users, ok := usedBy[p.Name]
+		if !ok {
+			return nil
+		}
+		ps := users.ToSlice()
+		slices.Sort(ps)
+		p.UsedBy = ps
+		return nil
+	})
+
+	slog.Info("Package uses graph built")
+}
+
+// resolveCalls keeps the call targets that are the entry of another function.
+func resolveCalls(fn *entity.Function, targets []uint64, entries map[uint64]*entity.Function) []uint64 {
+	ret := make([]uint64, 0, len(targets))
+	for _, addr := range targets {
+		if callee, ok := entries[addr]; ok && callee != fn {
+			ret = append(ret, addr)
+		}
+	}
+	return ret
+}
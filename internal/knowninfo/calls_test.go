@@ -0,0 +1,50 @@
+package knowninfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestResolveCalls(t *testing.T) {
+	fn := &entity.Function{Name: "f", Addr: 0x1000}
+	g := &entity.Function{Name: "g", Addr: 0x2000}
+	entries := map[uint64]*entity.Function{fn.Addr: fn, g.Addr: g}
+
+	// self calls and targets inside functions are dropped
+	assert.Equal(t, []uint64{0x2000}, resolveCalls(fn, []uint64{0x1000, 0x1800, 0x2000}, entries))
+	assert.Empty(t, resolveCalls(fn, nil, entries))
+}
+
+func TestUpdateUsedBy(t *testing.T) {
+	k := &KnownInfo{}
+	k.Deps = NewDependencies(k)
+
+	put := func(name string, fns ...*entity.Function) {
+		p := entity.NewPackage()
+		p.Name = name
+		p.Files = []*entity.File{{FilePath: name + "/a.go", Functions: fns}}
+		k.Deps.Trie.Put(name, p)
+	}
+
+	put("main",
+		&entity.Function{Name: "main", Addr: 0x1000, Calls: []uint64{0x1100, 0x2000, 0x3000}},
+		&entity.Function{Name: "helper", Addr: 0x1100, Calls: []uint64{0x2000}},
+	)
+	put("example.com/a", &entity.Function{Name: "A", Addr: 0x2000, Calls: []uint64{0x3000, 0x9000}})
+	put("example.com/a/b", &entity.Function{Name: "B", Addr: 0x3000})
+	put("fmt", &entity.Function{Name: "Println", Addr: 0x4000})
+
+	k.Deps.UpdateUsedBy()
+
+	get := func(name string) *entity.Package {
+		p, _ := k.Deps.GetPackage(name)
+		return p
+	}
+	assert.Empty(t, get("main").UsedBy)
+	assert.Equal(t, []string{"main"}, get("example.com/a").UsedBy)
+	assert.Equal(t, []string{"example.com/a", "main"}, get("example.com/a/b").UsedBy)
+	assert.Empty(t, get("fmt").UsedBy)
+}
@@ -78,7 +78,7 @@ func (m *Dependencies) AddModules(mods []*debug.Module, typ entity.PackageType)
 	}
 }
 
-func (m *Dependencies) FinishLoad(imports bool) {
+func (m *Dependencies) FinishLoad(imports bool) error {
 	type pair struct {
 		m  entity.PackageMap
 		tc *trie.PathTrie[*entity.Package]
@@ -108,8 +108,9 @@ func (m *Dependencies) FinishLoad(imports bool) {
 	}
 
 	if imports {
-		m.UpdateImportBy()
+		return m.UpdateImportBy()
 	}
+	return nil
 }
 
 // ClearCaches releases per-package file and function caches.
@@ -153,7 +154,15 @@ func (m *Dependencies) AddFromPclntab(gp *gore.Package, typ entity.PackageType,
 		return w.GetFunctionSize(f.Offset, m.k.VersionFlag.Meq125)
 	}
 
-	p := entity.NewPackageWithGorePackage(gp, name, typ, pclntab, getCodeSize, isWasm)
+	getPclnSize := func(f *gore.Function) entity.PclnSymbolSize {
+		if !isWasm {
+			return entity.NewPclnSymbolSize(f.Func)
+		}
+
+		return entity.NewWasmPclnSymbolSize(f.Func, m.k.VersionFlag.Meq125)
+	}
+
+	p := entity.NewPackageWithGorePackage(gp, name, typ, pclntab, getCodeSize, getPclnSize)
 
 	if !isWasm {
 		// update addrs
@@ -15,9 +15,12 @@ import (
 
 	"github.com/Zxilly/go-size-analyzer/internal/disasm"
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 )
 
 func (k *KnownInfo) Disasm() error {
+	ctx := k.context()
+
 	k.KnownAddr.BuildSymbolCoverage()
 
 	startTime := time.Now()
@@ -57,15 +60,46 @@ func (k *KnownInfo) Disasm() error {
 		}
 	}()
 
-	var (
-		maxWorkers = runtime.NumCPU()
-		eg         = errgroup.Group{}
-	)
+	// functions by entry address, to resolve the call targets
+	var entries map[uint64]*entity.Function
+	if k.Calls {
+		if e.SupportsCalls() {
+			entries = make(map[uint64]*entity.Function)
+			for fn := range k.Deps.Functions {
+				entries[fn.Addr] = fn
+			}
+		} else {
+			slog.Warn("Call graph not supported on this architecture")
+		}
+	}
+
+	total := 0
+	for range k.Deps.Functions {
+		total++
+	}
+	counter := progress.NewCounter(k.Progress, progress.PhaseDisasm, total)
+
+	maxWorkers := runtime.NumCPU()
+	eg, egCtx := errgroup.WithContext(ctx)
 	eg.SetLimit(maxWorkers)
 
 	for fn := range k.Deps.Functions {
+		if egCtx.Err() != nil {
+			break
+		}
 		eg.Go(func() error {
-			candidates := e.Extract(fn.Addr, fn.Addr+fn.CodeSize)
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+
+			var candidates []disasm.PossibleStr
+			if entries != nil {
+				var targets []uint64
+				candidates, targets = e.ExtractWithCalls(fn.Addr, fn.Addr+fn.CodeSize)
+				fn.Calls = resolveCalls(fn, targets, entries)
+			} else {
+				candidates = e.Extract(fn.Addr, fn.Addr+fn.CodeSize)
+			}
 
 			lo.ForEach(candidates, func(p disasm.PossibleStr, _ int) {
 				resultChan <- result{
@@ -75,18 +109,24 @@ func (k *KnownInfo) Disasm() error {
 				}
 			})
 
+			counter.Add(1)
 			return nil
 		})
 	}
 
-	if err = eg.Wait(); err != nil {
-		slog.Error(fmt.Sprintf("Disassemble functions failed: %v", err))
-		return err
-	}
+	err = eg.Wait()
 
 	close(resultChan)
 	<-resultProcess.Done()
 
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("Disassemble functions failed: %v", err))
+		return err
+	}
+
 	slog.Info(fmt.Sprintf("Disassemble functions done, took %s, added %d, throw %d", time.Since(startTime), added, throw))
 
 	return nil
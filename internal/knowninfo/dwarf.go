@@ -14,6 +14,7 @@ import (
 
 	dwarfutil "github.com/Zxilly/go-size-analyzer/internal/dwarf"
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
 
@@ -250,6 +251,9 @@ func (k *KnownInfo) TryLoadDwarf() bool {
 
 	k.HasDWARF = true
 
+	ctx := k.context()
+	counter := progress.NewCounter(k.Progress, progress.PhaseDwarf, 0)
+
 	r := d.Reader()
 
 	type item struct {
@@ -270,7 +274,7 @@ func (k *KnownInfo) TryLoadDwarf() bool {
 	var feeder EntryFeeder
 	var entry *dwarf.Entry
 
-	for entry, err = r.Next(); entry != nil; entry, err = r.Next() {
+	for entry, err = r.Next(); entry != nil && ctx.Err() == nil; entry, err = r.Next() {
 		if err != nil {
 			slog.Warn(fmt.Sprintf("Failed to load DWARF: %v", err))
 			return false
@@ -278,6 +282,8 @@ func (k *KnownInfo) TryLoadDwarf() bool {
 
 		switch entry.Tag {
 		case dwarf.TagCompileUnit:
+			counter.Add(1)
+
 			var ok bool
 			feeder, ok = k.GetDwarfCompileUnitFeeder(d, entry, ptrSize)
 			if !ok {
@@ -301,5 +307,5 @@ func (k *KnownInfo) TryLoadDwarf() bool {
 	close(entryChan)
 	processing.Wait()
 
-	return true
+	return ctx.Err() == nil
 }
@@ -0,0 +1,209 @@
+package knowninfo
+
+import (
+	"cmp"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// autogeneratedFile is the file of the wrappers generated by the compiler.
+const autogeneratedFile = "<autogenerated>"
+
+// closureSuffix matches the last part of the names of closures and go and
+// defer wrappers.
+var closureSuffix = regexp.MustCompile(`^(func|gowrap|deferwrap)\d+$`)
+
+// nestedSuffix matches the last part of the names of nested closures, numbered
+// without a prefix after the closure, e.g. F.func1.2. The user init functions
+// are numbered the same way, e.g. init.0, and are not closures.
+var nestedSuffix = regexp.MustCompile(`^\d+$`)
+
+// rangeSuffix matches the suffix of the bodies of range-over-func loops.
+var rangeSuffix = regexp.MustCompile(`-range\d+$`)
+
+// wrapperParent returns the name of the function the wrapper name belongs to,
+// ok is false if name is not of a closure, a method value or a go or defer wrapper.
+func wrapperParent(name string) (parent string, ok bool) {
+	if parent, ok = strings.CutSuffix(name, "-fm"); ok {
+		return parent, true
+	}
+	if loc := rangeSuffix.FindStringIndex(name); loc != nil {
+		return name[:loc[0]], true
+	}
+
+	i := strings.LastIndexByte(name, '.')
+	if i <= 0 {
+		return "", false
+	}
+	parent, last := name[:i], name[i+1:]
+	if closureSuffix.MatchString(last) {
+		return parent, true
+	}
+	if !nestedSuffix.MatchString(last) {
+		return "", false
+	}
+	// a number only follows a closure or another number
+	j := strings.LastIndexByte(parent, '.')
+	if j < 0 {
+		return "", false
+	}
+	if prev := parent[j+1:]; closureSuffix.MatchString(prev) || nestedSuffix.MatchString(prev) {
+		return parent, true
+	}
+	return "", false
+}
+
+// methodCounterpart returns the method of the value receiver for a method of
+// the pointer receiver, and the other way around, e.g. T.M for (*T).M.
+func methodCounterpart(name string) (string, bool) {
+	if rest, ok := strings.CutPrefix(name, "(*"); ok {
+		recv, method, ok := strings.Cut(rest, ").")
+		if !ok {
+			return "", false
+		}
+		return recv + "." + method, true
+	}
+
+	// the receiver may hold dots in the type arguments
+	end := strings.LastIndexByte(name, ']')
+	i := strings.IndexByte(name[end+1:], '.')
+	if i < 0 {
+		return "", false
+	}
+	i += end + 1
+	return "(*" + name[:i] + ")" + name[i:], true
+}
+
+// qualifiedPackage returns the package path of a function name qualified by
+// its package, e.g. example.com/a for example.com/a.(*T).M.
+func qualifiedPackage(name string) (string, bool) {
+	end := strings.IndexAny(name, "([")
+	if end < 0 {
+		end = len(name)
+	}
+	start := strings.LastIndexByte(name[:end], '/') + 1
+	dot := strings.IndexByte(name[start:end], '.')
+	if dot <= 0 {
+		return "", false
+	}
+	return name[:start+dot], true
+}
+
+// FoldWrappers folds the closures, method values, go and defer wrappers and
+// the autogenerated method wrappers into the function they belong to, so the
+// full size of a function is what is written in the source. The wrappers
+// named by a package are moved out of the generated packages first.
+// It should run after the functions are loaded, before the sizes are calculated.
+func (k *KnownInfo) FoldWrappers() {
+	slog.Info("Folding wrappers...")
+
+	k.moveGeneratedWrappers()
+
+	folded := 0
+	_ = k.Deps.Trie.Walk(func(_ string, p *entity.Package) error {
+		folded += foldPackageWrappers(p)
+		return nil
+	})
+
+	slog.Info("Wrappers folded", "count", folded)
+}
+
+// moveGeneratedWrappers moves the autogenerated functions of the generated
+// packages to the package named by the function name.
+func (k *KnownInfo) moveGeneratedWrappers() {
+	_ = k.Deps.Trie.Walk(func(_ string, p *entity.Package) error {
+		if p.Type != entity.PackageTypeGenerated {
+			return nil
+		}
+
+		moved := utils.NewSet[*entity.Function]()
+		for _, f := range p.Files {
+			if f.FilePath != autogeneratedFile {
+				continue
+			}
+			for _, fn := range f.Functions {
+				name, ok := qualifiedPackage(fn.Name)
+				if !ok || name == p.Name {
+					continue
+				}
+				owner, ok := k.Deps.GetPackage(name)
+				if !ok || owner.Type == entity.PackageTypeGenerated {
+					continue
+				}
+				if owner.AddFuncIfNotExists(autogeneratedFile, fn) {
+					moved.Add(fn)
+				}
+			}
+		}
+		p.RemoveFunctions(moved)
+		return nil
+	})
+}
+
+// foldPackageWrappers folds the wrappers of p, it returns the count of the folded ones.
+func foldPackageWrappers(p *entity.Package) int {
+	byName := make(map[string]*entity.Function)
+	generated := utils.NewSet[*entity.Function]()
+	for _, f := range p.Files {
+		for _, fn := range f.Functions {
+			name := localName(p.Name, fn)
+			if _, ok := byName[name]; !ok {
+				byName[name] = fn
+			}
+			if f.FilePath == autogeneratedFile {
+				generated.Add(fn)
+			}
+		}
+	}
+
+	parentOf := func(name, file string) *entity.Function {
+		// the parent of a closure may be inlined everywhere, try the enclosing ones
+		for cur, ok := wrapperParent(name); ok; cur, ok = wrapperParent(cur) {
+			if parent, found := byName[cur]; found {
+				return parent
+			}
+			if counterpart, isMethod := methodCounterpart(cur); isMethod && strings.HasSuffix(name, "-fm") {
+				if parent, found := byName[counterpart]; found {
+					return parent
+				}
+			}
+		}
+		if file == autogeneratedFile {
+			// both methods may be wrappers, e.g. the ones of a promoted method
+			if counterpart, ok := methodCounterpart(name); ok {
+				if parent, found := byName[counterpart]; found && !generated.Contains(parent) {
+					return parent
+				}
+			}
+		}
+		return nil
+	}
+
+	folded := utils.NewSet[*entity.Function]()
+	for _, f := range p.Files {
+		for _, fn := range f.Functions {
+			parent := parentOf(localName(p.Name, fn), f.FilePath)
+			if parent == nil || parent == fn {
+				continue
+			}
+			parent.Wrappers = append(parent.Wrappers, fn)
+			folded.Add(fn)
+		}
+	}
+	if len(folded) == 0 {
+		return 0
+	}
+
+	p.RemoveFunctions(folded)
+	for fn := range p.Functions {
+		slices.SortFunc(fn.Wrappers, func(a, b *entity.Function) int {
+			return cmp.Compare(a.Addr, b.Addr)
+		})
+	}
+	return len(folded)
+}
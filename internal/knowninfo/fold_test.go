@@ -0,0 +1,166 @@
+package knowninfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+func TestWrapperParent(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent string
+		ok     bool
+	}{
+		{"Foo.func1", "Foo", true},
+		{"Foo.func1.2", "Foo.func1", true},
+		{"Foo.func1.2.3", "Foo.func1.2", true},
+		{"Foo.gowrap1.2", "Foo.gowrap1", true},
+		{"init.0.func1", "init.0", true},
+		{"init.0", "", false},
+		{"init.12", "", false},
+		{"T.0", "", false},
+		{"0", "", false},
+		{"Foo.deferwrap1", "Foo", true},
+		{"Foo.gowrap2", "Foo", true},
+		{"(*T).Method-fm", "(*T).Method", true},
+		{"Foo-range1", "Foo", true},
+		{"Map[go.shape.int].func1", "Map[go.shape.int]", true},
+		{"glob..func1", "glob.", true},
+		{"glob.", "", false},
+		{"Foo", "", false},
+		{"T.Method", "", false},
+		{"Map[go.shape.int]", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, ok := wrapperParent(tt.name)
+			assert.Equal(t, tt.parent, parent)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestMethodCounterpart(t *testing.T) {
+	got, ok := methodCounterpart("(*T).M")
+	assert.True(t, ok)
+	assert.Equal(t, "T.M", got)
+
+	got, ok = methodCounterpart("T.M")
+	assert.True(t, ok)
+	assert.Equal(t, "(*T).M", got)
+
+	got, ok = methodCounterpart("List[go.shape.struct { X int }].Len")
+	assert.True(t, ok)
+	assert.Equal(t, "(*List[go.shape.struct { X int }]).Len", got)
+
+	_, ok = methodCounterpart("Foo")
+	assert.False(t, ok)
+}
+
+func TestQualifiedPackage(t *testing.T) {
+	got, ok := qualifiedPackage("example.com/a.(*T).M")
+	assert.True(t, ok)
+	assert.Equal(t, "example.com/a", got)
+
+	got, ok = qualifiedPackage("main.T.M")
+	assert.True(t, ok)
+	assert.Equal(t, "main", got)
+
+	got, ok = qualifiedPackage("example.com/a.Map[example.com/b.T]")
+	assert.True(t, ok)
+	assert.Equal(t, "example.com/a", got)
+
+	_, ok = qualifiedPackage("(*T).M")
+	assert.False(t, ok)
+}
+
+func TestFoldWrappers(t *testing.T) {
+	k := &KnownInfo{}
+	k.Deps = NewDependencies(k)
+
+	put := func(name string, typ entity.PackageType, files map[string][]*entity.Function) *entity.Package {
+		p := entity.NewPackage()
+		p.Name = name
+		p.Type = typ
+		for _, path := range utils.SortedKeys(files) {
+			p.Files = append(p.Files, &entity.File{FilePath: path, PkgName: name, Functions: files[path]})
+		}
+		k.Deps.Trie.Put(name, p)
+		return p
+	}
+
+	fn := func(name string, addr, size uint64) *entity.Function {
+		return &entity.Function{Name: name, Addr: addr, CodeSize: size, Type: entity.FuncTypeFunction}
+	}
+	method := func(recv, name string, addr, size uint64) *entity.Function {
+		return &entity.Function{Name: name, Receiver: recv, Addr: addr, CodeSize: size, Type: entity.FuncTypeMethod}
+	}
+
+	run := fn("Run", 0x1000, 100)
+	closure := fn("Run.func1", 0x1100, 20)
+	nested := fn("Run.func1.1", 0x1200, 5)
+	deferWrap := fn("Run.deferwrap1", 0x1050, 8)
+	orphan := fn("Gone.func1", 0x1300, 7)
+	genInit := fn("init", 0x1400, 40)
+	userInit := fn("init.0", 0x1500, 30)
+	initClosure := fn("init.0.func1", 0x1600, 4)
+	value := method("T", "String", 0x2000, 50)
+	ptrWrapper := method("*T", "String", 0x2100, 10)
+	methodValue := method("*T", "String-fm", 0x2200, 6)
+
+	main := put("main", entity.PackageTypeMain, map[string][]*entity.Function{
+		"main.go":         {run, closure, nested, deferWrap, orphan, genInit, userInit, initClosure},
+		"t.go":            {value},
+		autogeneratedFile: {ptrWrapper, methodValue},
+	})
+
+	generatedWrapper := &entity.Function{Name: "main.(*T).Close", Addr: 0x3000, CodeSize: 12, Type: entity.FuncTypeFunction}
+	eq := fn("type:.eq.[2]interface {}", 0x3100, 30)
+	generated := put("", entity.PackageTypeGenerated, map[string][]*entity.Function{
+		autogeneratedFile: {generatedWrapper, eq},
+	})
+
+	k.FoldWrappers()
+
+	var top []*entity.Function
+	for _, f := range main.Files {
+		top = append(top, f.Functions...)
+	}
+	assert.ElementsMatch(t, []*entity.Function{run, orphan, genInit, userInit, value, generatedWrapper}, top)
+
+	assert.Equal(t, []*entity.Function{deferWrap, closure}, run.Wrappers)
+	assert.Equal(t, []*entity.Function{nested}, closure.Wrappers)
+	assert.Equal(t, uint64(133), run.FullSize())
+
+	// a user init function is not a wrapper of the generated init
+	assert.Empty(t, genInit.Wrappers)
+	assert.Equal(t, []*entity.Function{initClosure}, userInit.Wrappers)
+
+	assert.Equal(t, []*entity.Function{ptrWrapper}, value.Wrappers)
+	assert.Equal(t, []*entity.Function{methodValue}, ptrWrapper.Wrappers)
+	assert.Equal(t, uint64(66), value.FullSize())
+
+	// the autogenerated file of main is left with the moved wrapper only
+	require.Len(t, main.Files, 3)
+	for _, f := range main.Files {
+		if f.FilePath == autogeneratedFile {
+			assert.Equal(t, []*entity.Function{generatedWrapper}, f.Functions)
+		}
+	}
+
+	require.Len(t, generated.Files, 1)
+	assert.Equal(t, []*entity.Function{eq}, generated.Files[0].Functions)
+
+	// all the functions are still there for the size calculation
+	count := 0
+	for range main.Functions {
+		count++
+	}
+	assert.Equal(t, 12, count)
+}
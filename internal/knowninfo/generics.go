@@ -0,0 +1,107 @@
+package knowninfo
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// localName returns the function name without the package path as the
+// linker names it, e.g. (*List[go.shape.int]).Push.
+func localName(pkg string, fn *entity.Function) string {
+	// the names from DWARF are qualified by the package
+	if rest, ok := strings.CutPrefix(fn.Name, pkg+"."); ok && pkg != "" {
+		return rest
+	}
+	if fn.Type != entity.FuncTypeMethod || fn.Receiver == "" {
+		return fn.Name
+	}
+	recv := fn.Receiver
+	if strings.HasPrefix(recv, "*") {
+		recv = "(" + recv + ")"
+	}
+	return recv + "." + fn.Name
+}
+
+// splitTypeArgs splits the name of an instantiation into the name of the
+// generic function and the type arguments, ok is false if name is not an
+// instantiation. The type arguments may hold brackets themselves, e.g.
+// Map[go.shape.[]int,go.shape.map[string]int].
+func splitTypeArgs(name string) (origin, args string, ok bool) {
+	start := strings.IndexByte(name, '[')
+	if start < 0 {
+		return name, "", false
+	}
+
+	depth := 0
+	for i := start; i < len(name); i++ {
+		switch name[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				args = name[start+1 : i]
+				if args == "" {
+					return name, "", false
+				}
+				return name[:start] + name[i+1:], args, true
+			}
+		}
+	}
+
+	return name, "", false
+}
+
+// CollectGenerics groups the instantiations of the generic functions by the
+// generic function, found by the type arguments in the function names.
+// The closures of an instantiation are grouped on their own, e.g. Map.func1.
+// It should run after the package tree is loaded.
+func (k *KnownInfo) CollectGenerics() []*entity.Generic {
+	generics := make(map[[2]string]*entity.Generic)
+
+	_ = k.Deps.Trie.Walk(func(_ string, p *entity.Package) error {
+		for fn := range p.Functions {
+			origin, args, ok := splitTypeArgs(localName(p.Name, fn))
+			if !ok {
+				continue
+			}
+
+			key := [2]string{p.Name, origin}
+			g, ok := generics[key]
+			if !ok {
+				g = &entity.Generic{
+					Package:   p.Name,
+					Name:      origin,
+					Instances: make([]*entity.Instance, 0),
+				}
+				generics[key] = g
+			}
+
+			size := fn.Size()
+			g.Size += size
+			g.Instances = append(g.Instances, &entity.Instance{
+				TypeArgs: args,
+				Shape:    strings.Contains(args, entity.ShapePrefix),
+				Addr:     fn.Addr,
+				Size:     size,
+			})
+		}
+		return nil
+	})
+
+	ret := make([]*entity.Generic, 0, len(generics))
+	for _, g := range generics {
+		slices.SortFunc(g.Instances, func(a, b *entity.Instance) int {
+			return cmp.Or(-cmp.Compare(a.Size, b.Size), cmp.Compare(a.TypeArgs, b.TypeArgs))
+		})
+		ret = append(ret, g)
+	}
+	slices.SortFunc(ret, func(a, b *entity.Generic) int {
+		return cmp.Or(-cmp.Compare(a.Size, b.Size), cmp.Compare(a.Package, b.Package), cmp.Compare(a.Name, b.Name))
+	})
+
+	return ret
+}
@@ -0,0 +1,91 @@
+package knowninfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestSplitTypeArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		args   string
+		ok     bool
+	}{
+		{"Map[go.shape.int,go.shape.string]", "Map", "go.shape.int,go.shape.string", true},
+		{"(*List[go.shape.int]).Push", "(*List).Push", "go.shape.int", true},
+		{"Map[go.shape.[]int,go.shape.map[string]int].func1", "Map.func1", "go.shape.[]int,go.shape.map[string]int", true},
+		{"Sort[example.com/x.T]", "Sort", "example.com/x.T", true},
+		{"Map[...]", "Map", "...", true},
+		{"Println", "Println", "", false},
+		{"broken[go.shape.int", "broken[go.shape.int", "", false},
+		{"empty[]", "empty[]", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, args, ok := splitTypeArgs(tt.name)
+			assert.Equal(t, tt.origin, origin)
+			assert.Equal(t, tt.args, args)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	assert.Equal(t, "Map[go.shape.int]", localName("slices", &entity.Function{Name: "Map[go.shape.int]", Type: entity.FuncTypeFunction}))
+	assert.Equal(t, "(*List[go.shape.int]).Push", localName("list",
+		&entity.Function{Name: "Push", Receiver: "*List[go.shape.int]", Type: entity.FuncTypeMethod}))
+	assert.Equal(t, "Set[int].Add", localName("set",
+		&entity.Function{Name: "Add", Receiver: "Set[int]", Type: entity.FuncTypeMethod}))
+	// from DWARF
+	assert.Equal(t, "(*List[go.shape.int]).Push", localName("example.com/list",
+		&entity.Function{Name: "example.com/list.(*List[go.shape.int]).Push", Receiver: "(*List[go.shape.int])", Type: entity.FuncTypeMethod}))
+}
+
+func TestCollectGenerics(t *testing.T) {
+	k := &KnownInfo{}
+	k.Deps = NewDependencies(k)
+
+	put := func(name string, fns ...*entity.Function) {
+		p := entity.NewPackage()
+		p.Name = name
+		p.Files = []*entity.File{{FilePath: name + "/a.go", Functions: fns}}
+		k.Deps.Trie.Put(name, p)
+	}
+
+	put("slices",
+		&entity.Function{Name: "Sort[go.shape.int]", Addr: 0x1000, CodeSize: 100},
+		&entity.Function{Name: "Sort[go.shape.string]", Addr: 0x1100, CodeSize: 300},
+		&entity.Function{Name: "Sort[int]", Addr: 0x1200, CodeSize: 20},
+		&entity.Function{Name: "Index", Addr: 0x1300, CodeSize: 1000},
+	)
+	put("example.com/list",
+		&entity.Function{Name: "Push", Receiver: "*List[go.shape.int]", Type: entity.FuncTypeMethod, Addr: 0x2000, CodeSize: 50},
+		&entity.Function{Name: "Push", Receiver: "*List[go.shape.string]", Type: entity.FuncTypeMethod, Addr: 0x2100, CodeSize: 50},
+	)
+
+	generics := k.CollectGenerics()
+	require.Len(t, generics, 2)
+
+	assert.Equal(t, &entity.Generic{
+		Package: "slices",
+		Name:    "Sort",
+		Size:    420,
+		Instances: []*entity.Instance{
+			{TypeArgs: "go.shape.string", Shape: true, Addr: 0x1100, Size: 300},
+			{TypeArgs: "go.shape.int", Shape: true, Addr: 0x1000, Size: 100},
+			{TypeArgs: "int", Addr: 0x1200, Size: 20},
+		},
+	}, generics[0])
+
+	assert.Equal(t, "example.com/list", generics[1].Package)
+	assert.Equal(t, "(*List).Push", generics[1].Name)
+	assert.Equal(t, uint64(100), generics[1].Size)
+	// the same size is sorted by the type arguments
+	assert.Equal(t, "go.shape.int", generics[1].Instances[0].TypeArgs)
+}
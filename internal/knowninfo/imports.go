@@ -10,30 +10,49 @@ import (
 	"runtime"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/puzpuzpuz/xsync/v4"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
 
-func (m *Dependencies) UpdateImportBy() {
+func (m *Dependencies) UpdateImportBy() error {
 	slog.Info("Analyzing package imports...")
 
-	var (
-		maxWorkers = runtime.NumCPU()
-		eg         = errgroup.Group{}
-	)
+	total := 0
+	_ = m.Trie.Walk(func(_ string, _ *entity.Package) error {
+		total++
+		return nil
+	})
+	counter := progress.NewCounter(m.k.Progress, progress.PhaseImports, total)
+
+	maxWorkers := runtime.NumCPU()
+	eg, ctx := errgroup.WithContext(m.k.context())
 	eg.SetLimit(maxWorkers)
 
 	importedBy := xsync.NewMap[string, utils.Set[string]]()
+	resolver := newLocalSourceResolver(m.k.BuildInfo)
+	var missing atomic.Int64
 
 	_ = m.Trie.Walk(func(_ string, pkg *entity.Package) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		eg.Go(func() error {
 			fset := token.NewFileSet()
 			imports := utils.NewSet[string]()
+			// whether the package has a go file, and whether any is found
+			wanted, found := false, false
 			for _, f := range pkg.Files {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
 				if f.FilePath == "" || f.FilePath == "<autogenerated>" {
 					// not a real file
 					continue
@@ -44,10 +63,17 @@ func (m *Dependencies) UpdateImportBy() {
 					continue
 				}
 
+				wanted = true
+				local := resolver.resolve(pkg, f.FilePath)
+				if local == "" {
+					continue
+				}
+				found = true
+
 				// check for import statements
-				pf, err := parser.ParseFile(fset, f.FilePath, nil, parser.ImportsOnly)
+				pf, err := parser.ParseFile(fset, local, nil, parser.ImportsOnly)
 				if err != nil {
-					slog.Warn(fmt.Sprintf("failed to parse package %s file %s: %v", pkg.Name, f.FilePath, err))
+					slog.Warn(fmt.Sprintf("failed to parse package %s file %s: %v", pkg.Name, local, err))
 					continue
 				}
 				for _, imp := range pf.Imports {
@@ -56,6 +82,10 @@ func (m *Dependencies) UpdateImportBy() {
 				}
 			}
 
+			if wanted && !found {
+				missing.Add(1)
+			}
+
 			for _, imp := range imports.ToSlice() {
 				importedBy.Compute(imp, func(oldValue utils.Set[string], loaded bool) (newValue utils.Set[string], op xsync.ComputeOp) {
 					if loaded {
@@ -68,13 +98,25 @@ func (m *Dependencies) UpdateImportBy() {
 					return newValue, op
 				})
 			}
+
+			counter.Add(1)
 			return nil
 		})
 
 		return nil
 	})
 
-	_ = eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if err := m.k.context().Err(); err != nil {
+		return err
+	}
+
+	if n := missing.Load(); n > 0 {
+		slog.Warn(fmt.Sprintf("Sources of %d packages not found in the recorded paths, GOROOT or GOMODCACHE, "+
+			"run go mod download in the module to fetch them", n))
+	}
 
 	_ = m.Trie.Walk(func(_ string, pkg *entity.Package) error {
 		fs, ok := importedBy.Load(pkg.Name)
@@ -89,4 +131,6 @@ func (m *Dependencies) UpdateImportBy() {
 	})
 
 	slog.Info("Package imports analysis completed.")
+
+	return nil
 }
@@ -2,6 +2,7 @@
 
 package knowninfo
 
-func (m *Dependencies) UpdateImportBy() {
+func (m *Dependencies) UpdateImportBy() error {
 	// No-op for wasm
+	return nil
 }
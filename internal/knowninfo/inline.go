@@ -0,0 +1,213 @@
+package knowninfo
+
+import (
+	"cmp"
+	"debug/dwarf"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/ZxillyFork/gosym"
+
+	dwarfutil "github.com/Zxilly/go-size-analyzer/internal/dwarf"
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// inlineCall is an inlined subroutine, along with the ones inlined into it.
+type inlineCall struct {
+	origin   dwarf.Offset
+	ranges   [][2]uint64
+	children []*inlineCall
+}
+
+// readInlineCalls reads the children of the entry just read by r, and returns
+// the inlined subroutines among them, the ones in lexical blocks included.
+func readInlineCalls(r *dwarf.Reader, d *dwarf.Data) ([]*inlineCall, error) {
+	var roots []*inlineCall
+	// where the calls of each open level of children go
+	levels := []*[]*inlineCall{&roots}
+
+	for len(levels) > 0 {
+		e, err := r.Next()
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			break
+		}
+		if e.Tag == 0 {
+			levels = levels[:len(levels)-1]
+			continue
+		}
+
+		parent := levels[len(levels)-1]
+		var call *inlineCall
+		if e.Tag == dwarf.TagInlinedSubroutine {
+			origin, ok := e.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset)
+			ranges, err := d.Ranges(e)
+			if ok && err == nil {
+				call = &inlineCall{origin: origin, ranges: ranges}
+				*parent = append(*parent, call)
+			}
+		}
+
+		if e.Children {
+			if call != nil {
+				parent = &call.children
+			}
+			levels = append(levels, parent)
+		}
+	}
+
+	return roots, nil
+}
+
+// rangesSize returns the size of ranges not covered by cut.
+func rangesSize(ranges, cut [][2]uint64) uint64 {
+	cut = slices.Clone(cut)
+	slices.SortFunc(cut, func(a, b [2]uint64) int {
+		return cmp.Compare(a[0], b[0])
+	})
+
+	size := uint64(0)
+	for _, r := range ranges {
+		start, end := r[0], r[1]
+		for _, c := range cut {
+			if c[1] <= start || c[0] >= end {
+				continue
+			}
+			if c[0] > start {
+				size += c[0] - start
+			}
+			start = max(start, c[1])
+			if start >= end {
+				break
+			}
+		}
+		if start < end {
+			size += end - start
+		}
+	}
+	return size
+}
+
+// inlineKey identifies an inlined function by the package it is inlined into.
+type inlineKey struct {
+	pkg      string
+	function string
+}
+
+// sumInlines adds the code of calls inlined into the functions of pkg, each
+// byte counts for the innermost call. The code inlined from pkg itself is skipped.
+func sumInlines(pkg string, calls []*inlineCall, name func(dwarf.Offset) string, ret map[inlineKey]*entity.Inline) {
+	for _, call := range calls {
+		sumInlines(pkg, call.children, name, ret)
+
+		fn := name(call.origin)
+		if fn == "" {
+			continue
+		}
+		origin := (&gosym.Sym{Name: fn}).PackageName()
+		if origin == pkg {
+			continue
+		}
+
+		var cut [][2]uint64
+		for _, child := range call.children {
+			cut = append(cut, child.ranges...)
+		}
+		size := rangesSize(call.ranges, cut)
+
+		key := inlineKey{pkg: pkg, function: fn}
+		in, ok := ret[key]
+		if !ok {
+			in = &entity.Inline{Package: pkg, Function: fn, Origin: origin}
+			ret[key] = in
+		}
+		in.Calls++
+		in.Size += size
+	}
+}
+
+// LoadDwarfInlines fills Inlines with the code of the functions inlined into
+// the functions of other packages, read from the inlined subroutines of the
+// Go compile units. It should run after TryLoadDwarf.
+func (k *KnownInfo) LoadDwarfInlines() error {
+	slog.Info("Loading DWARF inlined subroutines...")
+
+	d, err := k.Wrapper.DWARF()
+	if err != nil {
+		return err
+	}
+
+	// the abstract origins are looked up with another reader
+	or := d.Reader()
+	names := make(map[dwarf.Offset]string)
+	name := func(off dwarf.Offset) string {
+		if n, ok := names[off]; ok {
+			return n
+		}
+		n := ""
+		or.Seek(off)
+		if e, err := or.Next(); err == nil && e != nil {
+			n, _ = e.Val(dwarf.AttrName).(string)
+		}
+		names[off] = n
+		return n
+	}
+
+	inlines := make(map[inlineKey]*entity.Inline)
+	ctx := k.context()
+	r := d.Reader()
+	pkg := ""
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		e, err := r.Next()
+		if err != nil {
+			return fmt.Errorf("read DWARF: %w", err)
+		}
+		if e == nil {
+			break
+		}
+
+		switch e.Tag {
+		case dwarf.TagCompileUnit:
+			pkg = ""
+			if lang, _ := e.Val(dwarf.AttrLanguage).(int64); lang == dwarfutil.DwLangGo {
+				pkg, _ = e.Val(dwarf.AttrName).(string)
+			}
+			if pkg == "" {
+				r.SkipChildren()
+			}
+		case dwarf.TagSubprogram:
+			if !e.Children {
+				continue
+			}
+			calls, err := readInlineCalls(r, d)
+			if err != nil {
+				return fmt.Errorf("read DWARF inlined subroutines: %w", err)
+			}
+			sumInlines(pkg, calls, name, inlines)
+		default:
+			if e.Children {
+				r.SkipChildren()
+			}
+		}
+	}
+
+	k.Inlines = make([]*entity.Inline, 0, len(inlines))
+	for _, in := range inlines {
+		if in.Size > 0 {
+			k.Inlines = append(k.Inlines, in)
+		}
+	}
+	slices.SortFunc(k.Inlines, func(a, b *entity.Inline) int {
+		return cmp.Or(-cmp.Compare(a.Size, b.Size), cmp.Compare(a.Package, b.Package), cmp.Compare(a.Function, b.Function))
+	})
+
+	slog.Info("Loaded DWARF inlined subroutines", "functions", len(k.Inlines))
+	return nil
+}
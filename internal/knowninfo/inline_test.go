@@ -0,0 +1,67 @@
+package knowninfo
+
+import (
+	"debug/dwarf"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestRangesSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges [][2]uint64
+		cut    [][2]uint64
+		want   uint64
+	}{
+		{"no cut", [][2]uint64{{0x10, 0x20}, {0x30, 0x38}}, nil, 0x18},
+		{"inside", [][2]uint64{{0x10, 0x20}}, [][2]uint64{{0x14, 0x18}}, 0xc},
+		{"unsorted", [][2]uint64{{0x10, 0x20}}, [][2]uint64{{0x1c, 0x30}, {0x00, 0x12}}, 0xa},
+		{"overlapped", [][2]uint64{{0x10, 0x20}}, [][2]uint64{{0x12, 0x18}, {0x14, 0x1a}}, 0x8},
+		{"all", [][2]uint64{{0x10, 0x20}}, [][2]uint64{{0x10, 0x20}}, 0},
+		{"outside", [][2]uint64{{0x10, 0x20}}, [][2]uint64{{0x20, 0x30}}, 0x10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rangesSize(tt.ranges, tt.cut))
+		})
+	}
+}
+
+func TestSumInlines(t *testing.T) {
+	names := map[dwarf.Offset]string{
+		1: "strconv.Itoa",
+		2: "strconv.small",
+		3: "main.helper",
+		4: "unicode/utf8.RuneLen",
+	}
+	name := func(off dwarf.Offset) string { return names[off] }
+
+	calls := []*inlineCall{
+		{origin: 1, ranges: [][2]uint64{{0x100, 0x140}}, children: []*inlineCall{
+			{origin: 2, ranges: [][2]uint64{{0x110, 0x120}}},
+		}},
+		{origin: 1, ranges: [][2]uint64{{0x200, 0x210}}},
+		// the code of the own package is skipped, not the calls inlined into it
+		{origin: 3, ranges: [][2]uint64{{0x300, 0x340}}, children: []*inlineCall{
+			{origin: 4, ranges: [][2]uint64{{0x300, 0x308}}},
+		}},
+		// unknown origins are skipped
+		{origin: 5, ranges: [][2]uint64{{0x400, 0x410}}},
+	}
+
+	ret := make(map[inlineKey]*entity.Inline)
+	sumInlines("main", calls, name, ret)
+	require.Len(t, ret, 3)
+
+	assert.Equal(t, &entity.Inline{Package: "main", Function: "strconv.Itoa", Origin: "strconv", Calls: 2, Size: 0x40},
+		ret[inlineKey{pkg: "main", function: "strconv.Itoa"}])
+	assert.Equal(t, &entity.Inline{Package: "main", Function: "strconv.small", Origin: "strconv", Calls: 1, Size: 0x10},
+		ret[inlineKey{pkg: "main", function: "strconv.small"}])
+	assert.Equal(t, &entity.Inline{Package: "main", Function: "unicode/utf8.RuneLen", Origin: "unicode/utf8", Calls: 1, Size: 0x8},
+		ret[inlineKey{pkg: "main", function: "unicode/utf8.RuneLen"}])
+}
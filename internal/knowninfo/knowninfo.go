@@ -1,6 +1,7 @@
 package knowninfo
 
 import (
+	"context"
 	"encoding/binary"
 	"log/slog"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/ZxillyFork/gore"
 
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/progress"
 	"github.com/Zxilly/go-size-analyzer/internal/wrapper"
 )
 
@@ -53,6 +55,22 @@ type KnownInfo struct {
 	VersionFlag VersionFlag
 
 	HasDWARF bool
+
+	// Calls records the direct calls of the functions while disassembling.
+	Calls bool
+	// Inlines is the code inlined from other packages, filled by LoadDwarfInlines.
+	Inlines []*entity.Inline
+
+	// Ctx stops the long running passes once done, nil never stops them.
+	Ctx      context.Context
+	Progress progress.Func
+}
+
+func (k *KnownInfo) context() context.Context {
+	if k.Ctx == nil {
+		return context.Background()
+	}
+	return k.Ctx
 }
 
 func (k *KnownInfo) LoadGoreInfo(f *gore.GoFile, isWasm bool) error {
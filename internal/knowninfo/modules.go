@@ -0,0 +1,107 @@
+package knowninfo
+
+import (
+	"cmp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func newModule(mod *debug.Module, typ entity.PackageType) *entity.Module {
+	m := &entity.Module{
+		Path:     mod.Path,
+		Version:  mod.Version,
+		Type:     typ,
+		Packages: make([]string, 0),
+	}
+	if mod.Replace != nil {
+		// packages keep the import path of the replaced module
+		m.Replace = mod.Replace.Path
+		m.Version = mod.Replace.Version
+	}
+	return m
+}
+
+// CollectModules groups the packages by the module owning them, which is the
+// module with the longest path prefixing the package path.
+// It should run after the package sizes are calculated.
+func (k *KnownInfo) CollectModules() []*entity.Module {
+	modules := make(map[string]*entity.Module)
+	var mainModule *entity.Module
+
+	std := &entity.Module{
+		Path:     entity.ModulePathStd,
+		Type:     entity.PackageTypeStd,
+		Packages: make([]string, 0),
+	}
+	unknown := &entity.Module{
+		Path:     entity.ModulePathUnknown,
+		Type:     entity.PackageTypeUnknown,
+		Packages: make([]string, 0),
+	}
+
+	if k.BuildInfo != nil && k.BuildInfo.ModInfo != nil {
+		info := k.BuildInfo.ModInfo
+		std.Version = info.GoVersion
+		for _, dep := range info.Deps {
+			modules[dep.Path] = newModule(dep, entity.PackageTypeVendor)
+		}
+		if info.Main.Path != "" {
+			mainModule = newModule(&info.Main, entity.PackageTypeMain)
+			modules[info.Main.Path] = mainModule
+		}
+	}
+
+	owner := func(p *entity.Package) *entity.Module {
+		name := p.Name
+		for name != "" {
+			if m, ok := modules[name]; ok {
+				return m
+			}
+			i := strings.LastIndexByte(name, '/')
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+
+		switch {
+		case p.Type == entity.PackageTypeMain && mainModule != nil:
+			return mainModule
+		case p.Type == entity.PackageTypeStd:
+			return std
+		default:
+			return unknown
+		}
+	}
+
+	_ = k.Deps.Trie.Walk(func(_ string, p *entity.Package) error {
+		m := owner(p)
+		m.Size += p.OwnSize()
+		// the packages created for the module paths only have no content
+		if len(p.Files) > 0 || len(p.Symbols) > 0 {
+			m.Packages = append(m.Packages, p.Name)
+		}
+		return nil
+	})
+
+	ret := make([]*entity.Module, 0, len(modules)+2)
+	for _, m := range modules {
+		ret = append(ret, m)
+	}
+	ret = append(ret, std, unknown)
+
+	ret = slices.DeleteFunc(ret, func(m *entity.Module) bool {
+		return len(m.Packages) == 0 && m.Size == 0
+	})
+	for _, m := range ret {
+		slices.Sort(m.Packages)
+	}
+	slices.SortFunc(ret, func(a, b *entity.Module) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	return ret
+}
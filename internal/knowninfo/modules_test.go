@@ -0,0 +1,85 @@
+package knowninfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/ZxillyFork/gore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestCollectModules(t *testing.T) {
+	k := &KnownInfo{
+		BuildInfo: &gore.BuildInfo{ModInfo: &debug.BuildInfo{
+			GoVersion: "go1.24.0",
+			Main:      debug.Module{Path: "example.com/app", Version: "(devel)"},
+			Deps: []*debug.Module{
+				{Path: "example.com/lib", Version: "v1.0.0"},
+				{Path: "example.com/lib/sub", Version: "v0.2.0"},
+				{Path: "example.com/old", Version: "v1.0.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v1.1.0"}},
+				{Path: "example.com/unused", Version: "v0.1.0"},
+			},
+		}},
+	}
+	k.Deps = NewDependencies(k)
+	k.Deps.AddModules(k.BuildInfo.ModInfo.Deps, entity.PackageTypeVendor)
+
+	put := func(name string, typ entity.PackageType, size uint64) {
+		p, ok := k.Deps.GetPackage(name)
+		if !ok {
+			p = entity.NewPackage()
+			k.Deps.Trie.Put(name, p)
+		}
+		p.Name = name
+		p.Type = typ
+		p.Size = size
+		p.Files = []*entity.File{{FilePath: name + "/a.go"}}
+	}
+
+	// sizes include the sub packages
+	put("example.com/lib", entity.PackageTypeVendor, 300)
+	put("example.com/lib/sub", entity.PackageTypeVendor, 100)
+	put("example.com/lib/internal/x", entity.PackageTypeVendor, 50)
+	put("example.com/old/pkg", entity.PackageTypeVendor, 20)
+	put("main", entity.PackageTypeMain, 40)
+	put("example.com/app/util", entity.PackageTypeMain, 10)
+	put("fmt", entity.PackageTypeStd, 500)
+	put("_cgo_", entity.PackageTypeCGO, 5)
+
+	require.NoError(t, k.Deps.FinishLoad(false))
+
+	assert.Equal(t, []*entity.Module{
+		{Path: "example.com/app", Version: "(devel)", Type: entity.PackageTypeMain,
+			Packages: []string{"example.com/app/util", "main"}, Size: 50},
+		{Path: "example.com/lib", Version: "v1.0.0", Type: entity.PackageTypeVendor,
+			Packages: []string{"example.com/lib", "example.com/lib/internal/x"}, Size: 200},
+		{Path: "example.com/lib/sub", Version: "v0.2.0", Type: entity.PackageTypeVendor,
+			Packages: []string{"example.com/lib/sub"}, Size: 100},
+		{Path: "example.com/old", Version: "v1.1.0", Replace: "example.com/fork", Type: entity.PackageTypeVendor,
+			Packages: []string{"example.com/old/pkg"}, Size: 20},
+		{Path: entity.ModulePathStd, Version: "go1.24.0", Type: entity.PackageTypeStd,
+			Packages: []string{"fmt"}, Size: 500},
+		{Path: entity.ModulePathUnknown, Type: entity.PackageTypeUnknown,
+			Packages: []string{"_cgo_"}, Size: 5},
+	}, k.CollectModules())
+}
+
+func TestCollectModulesWithoutBuildInfo(t *testing.T) {
+	k := &KnownInfo{}
+	k.Deps = NewDependencies(k)
+
+	p := entity.NewPackage()
+	p.Name = "main"
+	p.Type = entity.PackageTypeMain
+	p.Size = 10
+	p.Files = []*entity.File{{FilePath: "main.go"}}
+	k.Deps.Trie.Put("main", p)
+	require.NoError(t, k.Deps.FinishLoad(false))
+
+	assert.Equal(t, []*entity.Module{
+		{Path: entity.ModulePathUnknown, Type: entity.PackageTypeUnknown, Packages: []string{"main"}, Size: 10},
+	}, k.CollectModules())
+}
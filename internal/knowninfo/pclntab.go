@@ -93,36 +93,19 @@ func (k *KnownInfo) attributeFtab(addr, length uint64) {
 	slog.Info("Attributed ftab region", "totalSize", length, "attributed", attributed)
 }
 
-func (k *KnownInfo) analyzeWasmPclntabMeta(md gore.Moduledata) error {
+// checkWasmPclntab checks the pclntab regions of wasm, which are offsets in
+// the linear memory rather than file addresses.
+func checkWasmPclntab(md gore.Moduledata) error {
 	pclntab := md.PCLNTab()
-	if pclntab.Length == 0 {
-		slog.Warn("PCLNTab region not available")
-		return nil
-	}
-
 	pclntabEnd := pclntab.Address + pclntab.Length
 	if pclntabEnd < pclntab.Address {
 		return fmt.Errorf("wasm pclntab range overflow: start=%#x len=%#x", pclntab.Address, pclntab.Length)
 	}
 
 	ftab := md.FuncTab()
-	if ftab.Length == 0 {
-		attributed := k.attributeRangeByFunctionCount("pclntab:functions", pclntab.Address, pclntab.Length)
-		slog.Info("Wasm pclntab metadata analyzed", "totalAttributed", attributed)
-		return nil
-	}
-
-	if ftab.Address < pclntab.Address || ftab.Address > pclntabEnd {
+	if ftab.Length > 0 && (ftab.Address < pclntab.Address || ftab.Address > pclntabEnd) {
 		return fmt.Errorf("wasm functab outside pclntab: functab=%#x pclntab=[%#x,%#x)", ftab.Address, pclntab.Address, pclntabEnd)
 	}
-
-	totalAttributed := uint64(0)
-	pclntabPkg := k.getOrCreateVirtualPackage("runtime/pclntab", entity.PackageTypeGenerated)
-
-	totalAttributed += k.attributeGeneratedData(pclntabPkg, "pclntab:meta", pclntab.Address, ftab.Address-pclntab.Address)
-	totalAttributed += k.attributeRangeByFunctionCount("pclntab:functions", ftab.Address, pclntabEnd-ftab.Address)
-
-	slog.Info("Wasm pclntab metadata analyzed", "totalAttributed", totalAttributed)
 	return nil
 }
 
@@ -138,8 +121,11 @@ func (k *KnownInfo) AnalyzePclntabMeta() error {
 		return fmt.Errorf("pclntab meta analysis moduledata: %w", err)
 	}
 
+	// wasm shares the accounting below, its functions have their pcln size too
 	if k.Wrapper.GoArch() == "wasm" {
-		return k.analyzeWasmPclntabMeta(md)
+		if err = checkWasmPclntab(md); err != nil {
+			return err
+		}
 	}
 
 	// Attribute ftab region proportionally.
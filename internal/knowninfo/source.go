@@ -0,0 +1,177 @@
+//go:build !wasm
+
+package knowninfo
+
+import (
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"unicode"
+
+	"github.com/ZxillyFork/gore"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// goEnv returns the go env variable key, from the environment or the go command.
+func goEnv(key string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	out, err := exec.Command("go", "env", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// escapeModulePath escapes a module path or version as the module cache
+// does, an upper case letter is an exclamation mark and the lower case letter.
+func escapeModulePath(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// sourceResolver finds the local sources of the packages, the file paths
+// recorded in pclntab only exist on the machine that built the binary,
+// and are not even absolute with -trimpath.
+type sourceResolver struct {
+	goroot   string
+	modcache string
+	// skipStd is set if the local go version differs from the binary, the
+	// recorded paths of the standard library may be of another local version
+	skipStd bool
+
+	// modules by path, with the local directory of their sources
+	modules map[string]string
+}
+
+// newSourceResolver skips the standard library if goversion, the version of
+// the go installation at goroot, differs from the one that built the binary.
+func newSourceResolver(info *gore.BuildInfo, goroot, goversion, modcache string) *sourceResolver {
+	skipStd := false
+	if goroot != "" && info != nil && info.ModInfo != nil {
+		// the version may be followed by the experiments, e.g. go1.25.0 X:jsonv2
+		want, _, _ := strings.Cut(info.ModInfo.GoVersion, " ")
+		got, _, _ := strings.Cut(goversion, " ")
+		if want != "" && got != want {
+			slog.Warn("local go version differs from the binary, standard library sources skipped",
+				"local", got, "binary", want)
+			goroot = ""
+			skipStd = true
+		}
+	}
+
+	r := &sourceResolver{
+		goroot:   goroot,
+		modcache: modcache,
+		skipStd:  skipStd,
+		modules:  make(map[string]string),
+	}
+
+	if info == nil || info.ModInfo == nil {
+		return r
+	}
+
+	add := func(mod *debug.Module) {
+		if mod.Path == "" {
+			return
+		}
+		src := mod
+		if mod.Replace != nil {
+			src = mod.Replace
+		}
+
+		switch {
+		case src.Version == "" && filepath.IsAbs(src.Path):
+			// replaced by a local directory
+			r.modules[mod.Path] = src.Path
+		case src.Version != "" && src.Version != "(devel)" && modcache != "":
+			r.modules[mod.Path] = filepath.Join(modcache,
+				filepath.FromSlash(escapeModulePath(src.Path)+"@"+escapeModulePath(src.Version)))
+		}
+	}
+
+	for _, dep := range info.ModInfo.Deps {
+		add(dep)
+	}
+	// the main module is only in the module cache if installed with a version
+	add(&info.ModInfo.Main)
+
+	return r
+}
+
+// newLocalSourceResolver uses the GOROOT, GOVERSION and GOMODCACHE of the local go installation.
+func newLocalSourceResolver(info *gore.BuildInfo) *sourceResolver {
+	return newSourceResolver(info, goEnv("GOROOT"), goEnv("GOVERSION"), goEnv("GOMODCACHE"))
+}
+
+// packageDir returns the local directory of the package sources, empty if unknown.
+func (r *sourceResolver) packageDir(p *entity.Package) string {
+	if p.Type == entity.PackageTypeStd {
+		if r.goroot == "" {
+			return ""
+		}
+		return filepath.Join(r.goroot, "src", filepath.FromSlash(p.Name))
+	}
+
+	// the module with the longest path prefixing the package path
+	name := p.Name
+	for name != "" {
+		if dir, ok := r.modules[name]; ok {
+			return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(p.Name, name)))
+		}
+		i := strings.LastIndexByte(name, '/')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return ""
+}
+
+// resolve returns the local path of the source file of p recorded as file,
+// the recorded path if it is absolute and exists, or the file of the same
+// name in the package directory. It returns empty if neither exists.
+func (r *sourceResolver) resolve(p *entity.Package, file string) string {
+	exists := func(name string) bool {
+		st, err := os.Stat(name)
+		return err == nil && !st.IsDir()
+	}
+
+	// a relative path of -trimpath would be resolved in the current directory
+	skip := p.Type == entity.PackageTypeStd && r.skipStd
+	if !skip && filepath.IsAbs(file) && exists(file) {
+		return file
+	}
+
+	if rest, ok := strings.CutPrefix(file, "$GOROOT/"); ok && r.goroot != "" {
+		if local := filepath.Join(r.goroot, filepath.FromSlash(rest)); exists(local) {
+			return local
+		}
+	}
+
+	dir := r.packageDir(p)
+	if dir == "" {
+		return ""
+	}
+	// the recorded path may be of another OS
+	base := path.Base(strings.ReplaceAll(file, `\`, "/"))
+	if local := filepath.Join(dir, base); exists(local) {
+		return local
+	}
+
+	slog.Debug("source file not found", "package", p.Name, "file", file)
+	return ""
+}
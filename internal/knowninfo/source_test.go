@@ -0,0 +1,146 @@
+//go:build !js && !wasm
+
+package knowninfo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/ZxillyFork/gore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestEscapeModulePath(t *testing.T) {
+	assert.Equal(t, "github.com/!burnt!sushi/toml", escapeModulePath("github.com/BurntSushi/toml"))
+	assert.Equal(t, "golang.org/x/sys", escapeModulePath("golang.org/x/sys"))
+}
+
+func TestSourceResolver(t *testing.T) {
+	root := t.TempDir()
+	goroot := filepath.Join(root, "goroot")
+	modcache := filepath.Join(root, "mod")
+	local := filepath.Join(root, "local")
+
+	touch := func(parts ...string) string {
+		name := filepath.Join(parts...)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte("package x\n"), 0o644))
+		return name
+	}
+
+	fmtFile := touch(goroot, "src", "fmt", "print.go")
+	libFile := touch(modcache, "github.com", "!foo", "lib@v1.2.0", "sub", "a.go")
+	forkFile := touch(modcache, "example.com", "fork@v1.1.0", "b.go")
+	localFile := touch(local, "pkg", "c.go")
+	appFile := touch(modcache, "example.com", "app@v0.3.0", "util", "d.go")
+
+	r := newSourceResolver(&gore.BuildInfo{ModInfo: &debug.BuildInfo{
+		GoVersion: "go1.25.0",
+		Main:      debug.Module{Path: "example.com/app", Version: "v0.3.0"},
+		Deps: []*debug.Module{
+			{Path: "github.com/Foo/lib", Version: "v1.2.0"},
+			{Path: "example.com/old", Version: "v1.0.0", Replace: &debug.Module{Path: "example.com/fork", Version: "v1.1.0"}},
+			{Path: "example.com/dev", Version: "v1.0.0", Replace: &debug.Module{Path: local}},
+			{Path: "example.com/rel", Version: "v1.0.0", Replace: &debug.Module{Path: "../rel"}},
+		},
+	}}, goroot, "go1.25.0", modcache)
+
+	pkg := func(name string, typ entity.PackageType) *entity.Package {
+		p := entity.NewPackage()
+		p.Name = name
+		p.Type = typ
+		return p
+	}
+
+	tests := []struct {
+		name string
+		pkg  *entity.Package
+		file string
+		want string
+	}{
+		{"recorded path", pkg("fmt", entity.PackageTypeStd), fmtFile, fmtFile},
+		{"std trimmed", pkg("fmt", entity.PackageTypeStd), "fmt/print.go", fmtFile},
+		{"std goroot prefix", pkg("fmt", entity.PackageTypeStd), "$GOROOT/src/fmt/print.go", fmtFile},
+		{"std other machine", pkg("fmt", entity.PackageTypeStd), "/opt/go/src/fmt/print.go", fmtFile},
+		{"module trimmed", pkg("github.com/Foo/lib/sub", entity.PackageTypeVendor), "github.com/Foo/lib@v1.2.0/sub/a.go", libFile},
+		{"module windows", pkg("github.com/Foo/lib/sub", entity.PackageTypeVendor), `C:\go\pkg\mod\github.com\!foo\lib@v1.2.0\sub\a.go`, libFile},
+		{"replaced", pkg("example.com/old", entity.PackageTypeVendor), "example.com/fork@v1.1.0/b.go", forkFile},
+		{"local replace", pkg("example.com/dev/pkg", entity.PackageTypeVendor), "/build/dev/pkg/c.go", localFile},
+		{"relative replace", pkg("example.com/rel", entity.PackageTypeVendor), "example.com/rel/e.go", ""},
+		{"main installed", pkg("example.com/app/util", entity.PackageTypeMain), "example.com/app@v0.3.0/util/d.go", appFile},
+		{"unknown module", pkg("example.com/other", entity.PackageTypeVendor), "example.com/other@v1.0.0/f.go", ""},
+		{"missing file", pkg("github.com/Foo/lib/sub", entity.PackageTypeVendor), "github.com/Foo/lib@v1.2.0/sub/missing.go", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.resolve(tt.pkg, tt.file))
+		})
+	}
+}
+
+func TestSourceResolverWithoutBuildInfo(t *testing.T) {
+	r := newSourceResolver(nil, "", "", "")
+
+	p := entity.NewPackage()
+	p.Name = "fmt"
+	p.Type = entity.PackageTypeStd
+	assert.Empty(t, r.resolve(p, "fmt/print.go"))
+}
+
+func TestSourceResolverGoVersion(t *testing.T) {
+	goroot := t.TempDir()
+	name := filepath.Join(goroot, "src", "fmt", "print.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte("package fmt\n"), 0o644))
+
+	p := entity.NewPackage()
+	p.Name = "fmt"
+	p.Type = entity.PackageTypeStd
+
+	tests := []struct {
+		name   string
+		binary string
+		local  string
+		want   string
+	}{
+		{"same", "go1.25.0", "go1.25.0", name},
+		{"experiments", "go1.25.0 X:jsonv2", "go1.25.0", name},
+		{"unknown binary version", "", "go1.25.0", name},
+		{"unknown local version", "go1.25.0", "", ""},
+		{"patch differs", "go1.25.0", "go1.25.1", ""},
+		{"minor differs", "go1.24.3", "go1.25.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSourceResolver(&gore.BuildInfo{ModInfo: &debug.BuildInfo{GoVersion: tt.binary}}, goroot, tt.local, "")
+			assert.Equal(t, tt.want, r.resolve(p, "$GOROOT/src/fmt/print.go"))
+			assert.Equal(t, tt.want, r.resolve(p, "fmt/print.go"))
+			// the recorded path of another local toolchain is skipped too
+			assert.Equal(t, tt.want, r.resolve(p, name))
+		})
+	}
+}
+
+func TestSourceResolverRelativeRecordedPath(t *testing.T) {
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	name := filepath.Join("example.com", "app", "x.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte("package app\n"), 0o644))
+
+	p := entity.NewPackage()
+	p.Name = "example.com/app"
+	p.Type = entity.PackageTypeMain
+
+	// a -trimpath path is not looked up in the current directory
+	r := newSourceResolver(nil, "", "", "")
+	assert.Empty(t, r.resolve(p, "example.com/app/x.go"))
+	assert.Equal(t, filepath.Join(cwd, name), r.resolve(p, filepath.Join(cwd, name)))
+}
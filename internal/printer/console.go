@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"maps"
 	"slices"
+	"strconv"
 
 	"github.com/dustin/go-humanize"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -18,48 +19,94 @@ import (
 	"github.com/Zxilly/go-size-analyzer/internal/utils"
 )
 
+// entrySection is the entry type of the unknown part of a section
+const entrySection = "section"
+
 type CommonOption struct {
 	HideSections bool
 	HideMain     bool
 	HideStd      bool
+
+	// Modules lists the modules in place of the top level packages.
+	Modules bool
+	// Generics lists the generic functions with their instantiations.
+	Generics bool
 }
 
-func Text(r *result.Result, writer io.Writer, options *CommonOption) error {
-	slog.Info("Printing text report")
+func (o *CommonOption) hidden(typ entity.PackageType) bool {
+	return (o.HideMain && typ == entity.PackageTypeMain) ||
+		(o.HideStd && typ == entity.PackageTypeStd)
+}
 
-	t := table.NewWriter()
-	t.SetStyle(utils.GetTableStyle())
+type sizeEntry struct {
+	name    string
+	size    uint64
+	typ     string
+	percent string
 
-	allKnownSize := uint64(0)
+	// only set for packages with the imports
+	retained uint64
 
-	t.SetTitle("%s", r.Name)
-	t.AppendHeader(table.Row{"Percent", "Name", "Size", "Type"})
+	// only set for modules
+	version  string
+	packages int
+}
 
-	type sizeEntry struct {
-		name    string
-		size    uint64
-		typ     string
-		percent string
+// packagesCell is the package count of a module, empty for a section.
+func (e sizeEntry) packagesCell() string {
+	if e.typ == entrySection {
+		return ""
 	}
+	return strconv.Itoa(e.packages)
+}
+
+// retainedCell is the retained size of a package, empty if unknown.
+func (e sizeEntry) retainedCell() string {
+	if e.retained == 0 {
+		return ""
+	}
+	return humanize.Bytes(e.retained)
+}
+
+// collectEntries lists the top level packages or the modules, and the unknown
+// part of sections, sorted by size, along with the known size of the result.
+func collectEntries(r *result.Result, options *CommonOption) ([]sizeEntry, uint64) {
+	allKnownSize := uint64(0)
 
 	entries := make([]sizeEntry, 0)
 
-	pkgs := utils.Collect(maps.Values(r.Packages))
-	for _, p := range pkgs {
-		if options.HideMain && p.Type == entity.PackageTypeMain {
-			continue
+	if options.Modules {
+		for _, m := range r.Modules {
+			if options.hidden(m.Type) {
+				continue
+			}
+
+			allKnownSize += m.Size
+			entries = append(entries, sizeEntry{
+				name:     m.Path,
+				size:     m.Size,
+				typ:      m.Type,
+				percent:  utils.PercentString(float64(m.Size) / float64(r.Size)),
+				version:  m.VersionString(),
+				packages: len(m.Packages),
+			})
 		}
-		if options.HideStd && p.Type == entity.PackageTypeStd {
-			continue
+	} else {
+		pkgs := utils.Collect(maps.Values(r.Packages))
+		for _, p := range pkgs {
+			if options.hidden(p.Type) {
+				continue
+			}
+
+			allKnownSize += p.Size
+			entries = append(entries, sizeEntry{
+				name:     p.Name,
+				size:     p.Size,
+				typ:      p.Type,
+				percent:  utils.PercentString(float64(p.Size) / float64(r.Size)),
+				retained: p.Retained,
+			})
 		}
-
-		allKnownSize += p.Size
-		entries = append(entries, sizeEntry{
-			name:    p.Name,
-			size:    p.Size,
-			typ:     p.Type,
-			percent: utils.PercentString(float64(p.Size) / float64(r.Size)),
-		})
 	}
 
 	if !options.HideSections {
@@ -72,7 +119,7 @@ func Text(r *result.Result, writer io.Writer, options *CommonOption) error {
 			entries = append(entries, sizeEntry{
 				name:    s.Name,
 				size:    unknownSize,
-				typ:     "section",
+				typ:     entrySection,
 				percent: utils.PercentString(float64(unknownSize) / float64(r.Size)),
 			})
 		}
@@ -84,14 +131,55 @@ func Text(r *result.Result, writer io.Writer, options *CommonOption) error {
 		return -cmp.Compare(a.size, b.size)
 	})
 
-	for _, e := range entries {
-		t.AppendRow(table.Row{e.percent, e.name, humanize.Bytes(e.size), e.typ})
-	}
+	return entries, allKnownSize
+}
 
-	t.AppendFooter(table.Row{utils.PercentString(float64(allKnownSize) / float64(r.Size)), "Known", humanize.Bytes(allKnownSize)})
-	t.AppendFooter(table.Row{"100%", "Total", humanize.Bytes(r.Size)})
+func Text(r *result.Result, writer io.Writer, options *CommonOption) error {
+	slog.Info("Printing text report")
+
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	t.SetTitle("%s", r.Name)
 
-	data := []byte(t.Render() + "\n")
+	entries, allKnownSize := collectEntries(r, options)
+
+	if options.Modules {
+		t.AppendHeader(table.Row{"Percent", "Module", "Version", "Packages", "Size", "Type"})
+		for _, e := range entries {
+			t.AppendRow(table.Row{e.percent, e.name, e.version, e.packagesCell(), humanize.Bytes(e.size), e.typ})
+		}
+		t.AppendFooter(table.Row{utils.PercentString(float64(allKnownSize) / float64(r.Size)), "Known", "", "", humanize.Bytes(allKnownSize)})
+		t.AppendFooter(table.Row{"100%", "Total", "", "", humanize.Bytes(r.Size)})
+	} else if lo.SomeBy(entries, func(e sizeEntry) bool { return e.retained > 0 }) {
+		t.AppendHeader(table.Row{"Percent", "Name", "Size", "Retained", "Type"})
+		for _, e := range entries {
+			t.AppendRow(table.Row{e.percent, e.name, humanize.Bytes(e.size), e.retainedCell(), e.typ})
+		}
+		t.AppendFooter(table.Row{utils.PercentString(float64(allKnownSize) / float64(r.Size)), "Known", humanize.Bytes(allKnownSize)})
+		t.AppendFooter(table.Row{"100%", "Total", humanize.Bytes(r.Size)})
+	} else {
+		t.AppendHeader(table.Row{"Percent", "Name", "Size", "Type"})
+		for _, e := range entries {
+			t.AppendRow(table.Row{e.percent, e.name, humanize.Bytes(e.size), e.typ})
+		}
+		t.AppendFooter(table.Row{utils.PercentString(float64(allKnownSize) / float64(r.Size)), "Known", humanize.Bytes(allKnownSize)})
+		t.AppendFooter(table.Row{"100%", "Total", humanize.Bytes(r.Size)})
+	}
+
+	out := t.Render() + "\n"
+	if options.Generics {
+		if g := genericsText(r.Generics); g != "" {
+			out += "\n" + g
+		}
+	}
+	if in := inlinesText(r.Inlines); in != "" {
+		out += "\n" + in
+	}
+	for _, w := range r.Why {
+		out += "\n" + whyTable(w)
+	}
+	data := []byte(out)
 
 	slog.Info("Report rendered")
 
@@ -0,0 +1,174 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+)
+
+type DotOption struct {
+	CommonOption
+
+	HideGenerated bool
+	// MinSize hides the nodes smaller than it.
+	MinSize uint64
+}
+
+// dotColors are the fill colors of the node types
+var dotColors = map[string]string{
+	entity.PackageTypeMain:      "#ffcc80",
+	entity.PackageTypeStd:       "#90caf9",
+	entity.PackageTypeVendor:    "#a5d6a7",
+	entity.PackageTypeGenerated: "#e0e0e0",
+	entity.PackageTypeCGO:       "#ef9a9a",
+	entity.PackageTypeUnknown:   "#ce93d8",
+}
+
+// dotQuote quotes s as a DOT string, a newline is the centered line break of a label.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+type dotNode struct {
+	name string
+	typ  string
+	size uint64
+
+	// only set for modules
+	version string
+}
+
+type dotEdge struct {
+	from, to string
+}
+
+// importGraph collects the nodes and edges of the import graph, the nodes
+// are the packages or the modules. Both are sorted by name.
+func importGraph(r *result.Result, options *DotOption) ([]dotNode, []dotEdge) {
+	hidden := func(typ string, size uint64) bool {
+		return options.hidden(typ) ||
+			(options.HideGenerated && typ == entity.PackageTypeGenerated) ||
+			size < options.MinSize
+	}
+
+	// node of each package, missing if hidden
+	nodeOf := make(map[string]string)
+	nodes := make([]dotNode, 0)
+	var pkgs []*entity.Package
+
+	var walk func(ps entity.PackageMap)
+	walk = func(ps entity.PackageMap) {
+		for _, p := range ps {
+			pkgs = append(pkgs, p)
+			walk(p.SubPackages)
+		}
+	}
+	walk(r.Packages)
+
+	if options.Modules {
+		for _, m := range r.Modules {
+			if hidden(m.Type, m.Size) {
+				continue
+			}
+			nodes = append(nodes, dotNode{name: m.Path, typ: m.Type, size: m.Size, version: m.VersionString()})
+			for _, name := range m.Packages {
+				nodeOf[name] = m.Path
+			}
+		}
+	} else {
+		for _, p := range pkgs {
+			size := p.OwnSize()
+			if hidden(p.Type, size) {
+				continue
+			}
+			nodes = append(nodes, dotNode{name: p.Name, typ: p.Type, size: size})
+			nodeOf[p.Name] = p.Name
+		}
+	}
+
+	seen := make(map[dotEdge]bool)
+	edges := make([]dotEdge, 0)
+	for _, p := range pkgs {
+		to, ok := nodeOf[p.Name]
+		if !ok {
+			continue
+		}
+		for _, importer := range p.ImportedBy {
+			from, ok := nodeOf[importer]
+			e := dotEdge{from: from, to: to}
+			if !ok || from == to || seen[e] {
+				continue
+			}
+			seen[e] = true
+			edges = append(edges, e)
+		}
+	}
+
+	slices.SortFunc(nodes, func(a, b dotNode) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	slices.SortFunc(edges, func(a, b dotEdge) int {
+		return cmp.Or(cmp.Compare(a.from, b.from), cmp.Compare(a.to, b.to))
+	})
+
+	return nodes, edges
+}
+
+// Dot writes the import graph in the Graphviz DOT language, the node font
+// grows with the size and the fill color is by type.
+func Dot(r *result.Result, writer io.Writer, options *DotOption) error {
+	slog.Info("Printing import graph")
+
+	nodes, edges := importGraph(r, options)
+	if len(edges) == 0 {
+		slog.Warn("No import found, the imports analysis requires --imports and the package sources")
+	}
+
+	var maxSize uint64
+	for _, n := range nodes {
+		maxSize = max(maxSize, n.size)
+	}
+
+	w := bufio.NewWriter(writer)
+
+	_, _ = fmt.Fprintf(w, "digraph %s {\n", dotQuote(r.Name))
+	_, _ = fmt.Fprintln(w, "\trankdir=LR;")
+	_, _ = fmt.Fprintln(w, "\tnode [shape=box, style=filled];")
+
+	for _, n := range nodes {
+		fontSize := 10.0
+		if maxSize > 0 {
+			fontSize += 20 * math.Sqrt(float64(n.size)/float64(maxSize))
+		}
+		label := strings.TrimSpace(packageDisplayName(n.name)+" "+n.version) + "\n" + humanize.Bytes(n.size)
+		tooltip := fmt.Sprintf("%s, %d bytes", n.typ, n.size)
+		_, _ = fmt.Fprintf(w, "\t%s [label=%s, fillcolor=%s, fontsize=%.1f, tooltip=%s];\n",
+			dotQuote(n.name), dotQuote(label), dotQuote(dotColors[n.typ]), fontSize, dotQuote(tooltip))
+	}
+	for _, e := range edges {
+		_, _ = fmt.Fprintf(w, "\t%s -> %s;\n", dotQuote(e.from), dotQuote(e.to))
+	}
+
+	_, _ = fmt.Fprintln(w, "}")
+
+	err := w.Flush()
+
+	slog.Info("Import graph written")
+
+	return err
+}
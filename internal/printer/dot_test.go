@@ -0,0 +1,168 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+)
+
+// newTestImportResult adds the imports and the modules to the test result.
+func newTestImportResult() *result.Result {
+	r := newTestResult()
+
+	net := r.Packages["golang.org/x/net"]
+	http2 := net.SubPackages["http2"]
+
+	r.Packages["runtime"].ImportedBy = []string{"main", "golang.org/x/net/http2", "", "missing"}
+	http2.ImportedBy = []string{"main", "golang.org/x/net"}
+	net.ImportedBy = []string{"main"}
+	r.Packages[""].ImportedBy = []string{"runtime"}
+
+	r.Modules = []*entity.Module{
+		{Path: "example.com/app", Type: entity.PackageTypeMain, Packages: []string{"main"}, Size: 427},
+		{Path: "golang.org/x/net", Version: "v0.1.0", Type: entity.PackageTypeVendor, Packages: []string{"golang.org/x/net", "golang.org/x/net/http2"}, Size: 330},
+		{Path: entity.ModulePathStd, Type: entity.PackageTypeStd, Packages: []string{"runtime"}, Size: 440},
+		{Path: entity.ModulePathUnknown, Type: entity.PackageTypeGenerated, Packages: []string{""}, Size: 55},
+	}
+
+	return r
+}
+
+func nodeNames(nodes []dotNode) []string {
+	ret := make([]string, len(nodes))
+	for i, n := range nodes {
+		ret[i] = n.name
+	}
+	return ret
+}
+
+func TestImportGraph(t *testing.T) {
+	const (
+		generated = ""
+		net       = "golang.org/x/net"
+		http2     = "golang.org/x/net/http2"
+		main      = "main"
+		runtime   = "runtime"
+	)
+
+	tests := []struct {
+		name    string
+		options DotOption
+		nodes   []string
+		edges   []dotEdge
+	}{
+		{
+			name:    "all",
+			options: DotOption{},
+			nodes:   []string{generated, net, http2, main, runtime},
+			edges: []dotEdge{
+				{generated, runtime},
+				{net, http2},
+				{http2, runtime},
+				{main, net},
+				{main, http2},
+				{main, runtime},
+				{runtime, generated},
+			},
+		},
+		{
+			name:    "min size",
+			options: DotOption{MinSize: 100},
+			nodes:   []string{http2, main, runtime},
+			edges:   []dotEdge{{http2, runtime}, {main, http2}, {main, runtime}},
+		},
+		{
+			name:    "hide generated",
+			options: DotOption{HideGenerated: true},
+			nodes:   []string{net, http2, main, runtime},
+			edges:   []dotEdge{{net, http2}, {http2, runtime}, {main, net}, {main, http2}, {main, runtime}},
+		},
+		{
+			name:    "hide std",
+			options: DotOption{CommonOption: CommonOption{HideStd: true}},
+			nodes:   []string{generated, net, http2, main},
+			edges:   []dotEdge{{net, http2}, {main, net}, {main, http2}},
+		},
+		{
+			name:    "hide main",
+			options: DotOption{CommonOption: CommonOption{HideMain: true}},
+			nodes:   []string{generated, net, http2, runtime},
+			edges:   []dotEdge{{generated, runtime}, {net, http2}, {http2, runtime}, {runtime, generated}},
+		},
+		{
+			// the import of http2 by net is inside the module and dropped
+			name:    "modules",
+			options: DotOption{CommonOption: CommonOption{Modules: true}},
+			nodes:   []string{"example.com/app", net, "std", "unknown"},
+			edges: []dotEdge{
+				{"example.com/app", net},
+				{"example.com/app", "std"},
+				{net, "std"},
+				{"std", "unknown"},
+				{"unknown", "std"},
+			},
+		},
+		{
+			name:    "modules min size",
+			options: DotOption{CommonOption: CommonOption{Modules: true}, MinSize: 400},
+			nodes:   []string{"example.com/app", "std"},
+			edges:   []dotEdge{{"example.com/app", "std"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges := importGraph(newTestImportResult(), &tt.options)
+			assert.Equal(t, tt.nodes, nodeNames(nodes))
+			assert.Equal(t, tt.edges, edges)
+		})
+	}
+}
+
+func TestImportGraphNodes(t *testing.T) {
+	nodes, _ := importGraph(newTestImportResult(), &DotOption{})
+	require.Len(t, nodes, 5)
+
+	// the size of a package is without its sub packages
+	assert.Equal(t, dotNode{name: "golang.org/x/net", typ: entity.PackageTypeVendor, size: 0}, nodes[1])
+	assert.Equal(t, dotNode{name: "golang.org/x/net/http2", typ: entity.PackageTypeVendor, size: 330}, nodes[2])
+
+	nodes, _ = importGraph(newTestImportResult(), &DotOption{CommonOption: CommonOption{Modules: true}})
+	require.Len(t, nodes, 4)
+	assert.Equal(t, dotNode{name: "golang.org/x/net", typ: entity.PackageTypeVendor, size: 330, version: "v0.1.0"}, nodes[1])
+}
+
+func TestDotQuote(t *testing.T) {
+	assert.Equal(t, `"main"`, dotQuote("main"))
+	assert.Equal(t, `"a\"b"`, dotQuote(`a"b`))
+	assert.Equal(t, `"a\\b"`, dotQuote(`a\b`))
+	assert.Equal(t, `"a\\\"b"`, dotQuote(`a\"b`))
+	assert.Equal(t, `"main\n1.2 kB"`, dotQuote("main\n1.2 kB"))
+}
+
+func TestDot(t *testing.T) {
+	r := newTestImportResult()
+	r.Name = `bin\"app"`
+
+	var buf bytes.Buffer
+	require.NoError(t, Dot(r, &buf, &DotOption{CommonOption: CommonOption{Modules: true}, MinSize: 100}))
+
+	assert.Equal(t, `digraph "bin\\\"app\"" {
+	rankdir=LR;
+	node [shape=box, style=filled];
+	"example.com/app" [label="example.com/app\n427 B", fillcolor="#ffcc80", fontsize=29.7, tooltip="main, 427 bytes"];
+	"golang.org/x/net" [label="golang.org/x/net v0.1.0\n330 B", fillcolor="#a5d6a7", fontsize=27.3, tooltip="vendor, 330 bytes"];
+	"std" [label="std\n440 B", fillcolor="#90caf9", fontsize=30.0, tooltip="std, 440 bytes"];
+	"example.com/app" -> "golang.org/x/net";
+	"example.com/app" -> "std";
+	"golang.org/x/net" -> "std";
+}
+`, buf.String())
+}
@@ -0,0 +1,130 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// fatRow is an entry of the combined report with its size in every slice,
+// present is false for the slices without it.
+type fatRow struct {
+	name, typ string
+	sizes     []uint64
+	present   []bool
+	largest   uint64
+}
+
+// fatRows merges the entries of the slices by name, sorted by the largest size,
+// along with the known size of every slice.
+func fatRows(f *result.Fat, options *CommonOption) ([]*fatRow, []uint64) {
+	byName := make(map[string]*fatRow)
+	rows := make([]*fatRow, 0)
+	known := make([]uint64, len(f.Slices))
+
+	for i, s := range f.Slices {
+		entries, knownSize := collectEntries(s.Result, options)
+		known[i] = knownSize
+		for _, e := range entries {
+			key := e.typ + "\x00" + e.name
+			row, ok := byName[key]
+			if !ok {
+				row = &fatRow{name: e.name, typ: e.typ, sizes: make([]uint64, len(f.Slices)), present: make([]bool, len(f.Slices))}
+				byName[key] = row
+				rows = append(rows, row)
+			}
+			row.sizes[i] = e.size
+			row.present[i] = true
+			row.largest = max(row.largest, e.size)
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b *fatRow) int {
+		return cmp.Or(-cmp.Compare(a.largest, b.largest), cmp.Compare(a.name, b.name))
+	})
+	return rows, known
+}
+
+// fatTable renders the sizes of every slice side by side, code is applied to the names.
+func fatTable(f *result.Fat, options *CommonOption, code func(string) string) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	name := "Name"
+	if options.Modules {
+		name = "Module"
+	}
+	header := table.Row{name}
+	for _, s := range f.Slices {
+		header = append(header, s.Arch)
+	}
+	t.AppendHeader(append(header, "Type"))
+
+	rows, known := fatRows(f, options)
+	for _, r := range rows {
+		row := table.Row{code(r.name)}
+		for i := range f.Slices {
+			cell := ""
+			if r.present[i] {
+				cell = humanize.Bytes(r.sizes[i])
+			}
+			row = append(row, cell)
+		}
+		t.AppendRow(append(row, r.typ))
+	}
+
+	knownRow, totalRow := table.Row{"Known"}, table.Row{"Total"}
+	for i, s := range f.Slices {
+		knownRow = append(knownRow, fmt.Sprintf("%s (%s)", humanize.Bytes(known[i]),
+			utils.PercentString(float64(known[i])/float64(s.Size))))
+		totalRow = append(totalRow, humanize.Bytes(s.Size))
+	}
+	t.AppendFooter(knownRow)
+	t.AppendFooter(totalRow)
+	return t
+}
+
+// FatText renders the combined report of the slices of a universal binary.
+func FatText(f *result.Fat, writer io.Writer, options *CommonOption) error {
+	slog.Info("Printing combined text report")
+
+	t := fatTable(f, options, func(s string) string { return s })
+	t.SetTitle("%s (%s)", f.Name, humanize.Bytes(f.Size))
+
+	_, err := writer.Write([]byte(t.Render() + "\n"))
+
+	slog.Info("Report written")
+
+	return err
+}
+
+// FatMarkdown renders the combined report of the slices of a universal binary
+// for pull request comments.
+func FatMarkdown(f *result.Fat, writer io.Writer, options *CommonOption) error {
+	slog.Info("Printing combined markdown report")
+
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "## %s\n\n", MarkdownCode(f.Name))
+	_, _ = fmt.Fprintf(sb, "- **Total:** %s\n", humanize.Bytes(f.Size))
+	for _, s := range f.Slices {
+		_, _ = fmt.Fprintf(sb, "- **%s:** %s\n", s.Arch, humanize.Bytes(s.Size))
+	}
+	_, _ = fmt.Fprintf(sb, "\n%s\n", fatTable(f, options, MarkdownCode).RenderMarkdown())
+
+	_, err := writer.Write([]byte(sb.String()))
+
+	slog.Info("Report written")
+
+	return err
+}
@@ -0,0 +1,99 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// foldedFrame keeps the separator out of a frame, it would split the stack otherwise.
+func foldedFrame(name string) string {
+	return strings.ReplaceAll(name, ";", ":")
+}
+
+// Folded writes the result as folded stacks, one "frame;frame;frame bytes" line per
+// function or data symbol, for flamegraph.pl, inferno and speedscope.
+func Folded(r *result.Result, writer io.Writer, options *CommonOption) error {
+	slog.Info("Printing folded stacks")
+
+	w := bufio.NewWriter(writer)
+
+	line := func(stack []string, size uint64) {
+		if size == 0 {
+			return
+		}
+		frames := make([]string, len(stack))
+		for i, f := range stack {
+			frames[i] = foldedFrame(f)
+		}
+		_, _ = fmt.Fprintf(w, "%s %d\n", strings.Join(frames, ";"), size)
+	}
+
+	// the wrappers folded into a function are frames below it
+	var functionLines func(pkg string, stack []string, fn *entity.Function)
+	functionLines = func(pkg string, stack []string, fn *entity.Function) {
+		stack = append(stack, qualifiedFunctionName(pkg, fn))
+		line(stack, fn.Size())
+		for _, w := range fn.Wrappers {
+			functionLines(pkg, stack, w)
+		}
+	}
+
+	var walk func(p *entity.Package, stack []string)
+	walk = func(p *entity.Package, stack []string) {
+		stack = append(stack, packageDisplayName(p.Name))
+
+		hidden := (options.HideMain && p.Type == entity.PackageTypeMain) ||
+			(options.HideStd && p.Type == entity.PackageTypeStd)
+
+		if !hidden {
+			for _, f := range p.Files {
+				for _, fn := range f.Functions {
+					functionLines(p.Name, append(stack, f.FilePath), fn)
+				}
+			}
+
+			for _, s := range p.Symbols {
+				if s.Type != entity.AddrTypeData {
+					continue
+				}
+				if sect, inFile := findSection(r.Sections, s.Addr); sect != nil && !inFile {
+					// bss like, takes no space in the file
+					continue
+				}
+				line(append(stack, s.Name), s.Size)
+			}
+		}
+
+		for _, k := range utils.SortedKeys(p.SubPackages) {
+			walk(p.SubPackages[k], stack)
+		}
+	}
+
+	for _, k := range utils.SortedKeys(r.Packages) {
+		walk(r.Packages[k], nil)
+	}
+
+	if !options.HideSections {
+		for _, s := range r.Sections {
+			if s.OnlyInMemory || s.FileSize <= s.KnownSize {
+				continue
+			}
+			line([]string{s.Name}, s.FileSize-s.KnownSize)
+		}
+	}
+
+	err := w.Flush()
+
+	slog.Info("Folded stacks written")
+
+	return err
+}
@@ -0,0 +1,59 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestFolded(t *testing.T) {
+	const (
+		generated = "<autogenerated>;<autogenerated>;type:.eq.[2]string 55\n"
+		vendor    = "golang.org/x/net;golang.org/x/net/http2;/mod/golang.org/x/net/http2/frame.go;golang.org/x/net/http2.readFrame 330\n"
+		main      = "main;/src/app/main.go;main.main 110\n" +
+			"main;/src/app/main.go;main.*T.Run 220\n" +
+			"main;/src/app/main.go;main.*T.Run;main.(*T).Run-fm 33\n" +
+			"main;/src/app/main.go;main.F[go.shape.struct { X int }] 16\n" +
+			"main;main.table 64\n" +
+			"main;main.a:b 8\n"
+		std      = "runtime;/goroot/src/runtime/proc.go;runtime.schedule 440\n"
+		sections = ".text 96\n"
+	)
+
+	tests := []struct {
+		name    string
+		options CommonOption
+		want    string
+	}{
+		{"all", CommonOption{}, generated + vendor + main + std + sections},
+		{"hide main", CommonOption{HideMain: true}, generated + vendor + std + sections},
+		{"hide std", CommonOption{HideStd: true}, generated + vendor + main + sections},
+		{"hide sections", CommonOption{HideSections: true}, generated + vendor + main + std},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestResult()
+
+			// a space is kept, the count is split at the last one,
+			// a semicolon would split the frame
+			mainPkg := r.Packages["main"]
+			mainPkg.Files[0].Functions = append(mainPkg.Files[0].Functions,
+				newTestFunction("F[go.shape.struct { X int }]", 0x1600, 16, 0),
+				newTestFunction("zero", 0x1610, 0, 0))
+			mainPkg.Symbols = append(mainPkg.Symbols,
+				&entity.Symbol{Name: "main.a;b", Addr: 0x3040, Size: 8, Type: entity.AddrTypeData},
+				&entity.Symbol{Name: "main.text", Addr: 0x1700, Size: 8, Type: entity.AddrTypeText})
+
+			var buf bytes.Buffer
+			require.NoError(t, Folded(r, &buf, &tt.options))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
@@ -5,11 +5,16 @@ const (
 	FormatJSON = "json"
 	FormatHTML = "html"
 	FormatSVG  = "svg"
+
+	FormatMarkdown = "markdown"
+	FormatPprof    = "pprof"
+	FormatFolded   = "folded"
+	FormatDot      = "dot"
 )
 
 // SupportedFormats lists every format accepted by the printer package, in the
 // canonical order used by help text and test matrices.
-var SupportedFormats = []string{FormatText, FormatJSON, FormatHTML, FormatSVG}
+var SupportedFormats = []string{FormatText, FormatJSON, FormatHTML, FormatSVG, FormatMarkdown, FormatPprof, FormatFolded, FormatDot}
 
 // IsSupportedFormat reports whether name is one of SupportedFormats.
 func IsSupportedFormat(name string) bool {
@@ -0,0 +1,73 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// genericsLimit is the count of generic functions listed in the reports,
+// the json output holds all of them.
+const genericsLimit = 20
+
+// genericName returns the generic function name qualified by the package.
+func genericName(g *entity.Generic) string {
+	if g.Package == "" {
+		return g.Name
+	}
+	return g.Package + "." + g.Name
+}
+
+// shapeCount returns the count of the shape instantiations of g.
+func shapeCount(g *entity.Generic) int {
+	n := 0
+	for _, inst := range g.Instances {
+		if inst.Shape {
+			n++
+		}
+	}
+	return n
+}
+
+// genericsTable lists the largest generic functions with the most expensive
+// type arguments of each, code is applied to the names.
+func genericsTable(generics []*entity.Generic, code func(string) string) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+	t.AppendHeader(table.Row{"Generic", "Instances", "Shapes", "Size", "Largest Type Arguments", "Largest Size"})
+
+	var total uint64
+	for i, g := range generics {
+		total += g.Size
+		if i >= genericsLimit {
+			continue
+		}
+		largest := g.Instances[0]
+		t.AppendRow(table.Row{code(genericName(g)), strconv.Itoa(len(g.Instances)), strconv.Itoa(shapeCount(g)),
+			humanize.Bytes(g.Size), code(largest.TypeArgs), humanize.Bytes(largest.Size)})
+	}
+
+	name := "Total"
+	if len(generics) > genericsLimit {
+		name = fmt.Sprintf("Total, %d more not shown", len(generics)-genericsLimit)
+	}
+	t.AppendFooter(table.Row{name, "", "", humanize.Bytes(total)})
+	return t
+}
+
+// genericsText renders the generics table for the text report, empty without generics.
+func genericsText(generics []*entity.Generic) string {
+	if len(generics) == 0 {
+		return ""
+	}
+	t := genericsTable(generics, func(s string) string { return s })
+	t.SetTitle("Generics")
+	return t.Render() + "\n"
+}
@@ -0,0 +1,93 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// inlinesLimit is the count of package pairs listed in the reports,
+// the json output holds every inlined function.
+const inlinesLimit = 20
+
+// inlinePair is the code inlined from the package origin into the package pkg.
+type inlinePair struct {
+	pkg, origin string
+
+	functions, calls int
+	size             uint64
+}
+
+// inlinePairs sums the inlined functions by the package pair, the largest first.
+func inlinePairs(inlines []*entity.Inline) []*inlinePair {
+	byKey := make(map[[2]string]*inlinePair)
+	ret := make([]*inlinePair, 0)
+	for _, in := range inlines {
+		key := [2]string{in.Package, in.Origin}
+		p, ok := byKey[key]
+		if !ok {
+			p = &inlinePair{pkg: in.Package, origin: in.Origin}
+			byKey[key] = p
+			ret = append(ret, p)
+		}
+		p.functions++
+		p.calls += in.Calls
+		p.size += in.Size
+	}
+	slices.SortFunc(ret, func(a, b *inlinePair) int {
+		return cmp.Or(-cmp.Compare(a.size, b.size), cmp.Compare(a.pkg, b.pkg), cmp.Compare(a.origin, b.origin))
+	})
+	return ret
+}
+
+// inlinesSize returns the size of all the inlined code.
+func inlinesSize(inlines []*entity.Inline) uint64 {
+	var size uint64
+	for _, in := range inlines {
+		size += in.Size
+	}
+	return size
+}
+
+// inlinesTable lists the packages with the most code inlined from another
+// package, code is applied to the names.
+func inlinesTable(inlines []*entity.Inline, code func(string) string) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+	t.AppendHeader(table.Row{"Package", "Inlined From", "Functions", "Calls", "Size"})
+
+	pairs := inlinePairs(inlines)
+	for i, p := range pairs {
+		if i >= inlinesLimit {
+			break
+		}
+		t.AppendRow(table.Row{code(packageDisplayName(p.pkg)), code(packageDisplayName(p.origin)),
+			strconv.Itoa(p.functions), strconv.Itoa(p.calls), humanize.Bytes(p.size)})
+	}
+
+	name := "Total"
+	if len(pairs) > inlinesLimit {
+		name = fmt.Sprintf("Total, %d more not shown", len(pairs)-inlinesLimit)
+	}
+	t.AppendFooter(table.Row{name, "", "", "", humanize.Bytes(inlinesSize(inlines))})
+	return t
+}
+
+// inlinesText renders the inlines table for the text report, empty without inlines.
+func inlinesText(inlines []*entity.Inline) string {
+	if len(inlines) == 0 {
+		return ""
+	}
+	t := inlinesTable(inlines, func(s string) string { return s })
+	t.SetTitle("Inlined")
+	return t.Render() + "\n"
+}
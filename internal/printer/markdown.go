@@ -0,0 +1,128 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// markdownGroups is the order of the collapsible sections in the markdown report
+var markdownGroups = []string{
+	entity.PackageTypeMain,
+	entity.PackageTypeStd,
+	entity.PackageTypeVendor,
+	entity.PackageTypeGenerated,
+	entity.PackageTypeCGO,
+	entity.PackageTypeUnknown,
+	entrySection,
+}
+
+// MarkdownCode wraps s in a code span, so names like *T.M are not taken as emphasis.
+func MarkdownCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// MarkdownDetails writes a collapsible block, GitHub requires blank lines around the table in it.
+func MarkdownDetails(sb *strings.Builder, summary string, t table.Writer) {
+	_, _ = fmt.Fprintf(sb, "<details>\n<summary>%s</summary>\n\n%s\n\n</details>\n\n", summary, t.RenderMarkdown())
+}
+
+// Markdown renders a GitHub-flavored report for pull request comments,
+// each package or module type is a collapsible section.
+func Markdown(r *result.Result, writer io.Writer, options *CommonOption) error {
+	slog.Info("Printing markdown report")
+
+	entries, allKnownSize := collectEntries(r, options)
+
+	sb := new(strings.Builder)
+	_, _ = fmt.Fprintf(sb, "## %s\n\n", MarkdownCode(r.Name))
+	_, _ = fmt.Fprintf(sb, "- **Total:** %s\n", humanize.Bytes(r.Size))
+	_, _ = fmt.Fprintf(sb, "- **Known:** %s (%s)\n\n", humanize.Bytes(allKnownSize),
+		utils.PercentString(float64(allKnownSize)/float64(r.Size)))
+
+	for _, group := range markdownGroups {
+		t := table.NewWriter()
+		t.SetStyle(utils.GetTableStyle())
+		if options.Modules {
+			t.AppendHeader(table.Row{"Percent", "Module", "Version", "Packages", "Size"})
+		} else {
+			t.AppendHeader(table.Row{"Percent", "Name", "Size"})
+		}
+
+		var count int
+		var size uint64
+		for _, e := range entries {
+			if e.typ != group {
+				continue
+			}
+			count++
+			size += e.size
+			if options.Modules {
+				version := e.version
+				if version != "" {
+					version = MarkdownCode(version)
+				}
+				t.AppendRow(table.Row{e.percent, MarkdownCode(e.name), version, e.packagesCell(), humanize.Bytes(e.size)})
+			} else {
+				// an empty code span is not rendered as one
+				t.AppendRow(table.Row{e.percent, MarkdownCode(packageDisplayName(e.name)), humanize.Bytes(e.size)})
+			}
+		}
+		if count == 0 {
+			continue
+		}
+
+		unit := "packages"
+		switch {
+		case group == entrySection:
+			unit = "sections"
+		case options.Modules:
+			unit = "modules"
+		}
+		summary := fmt.Sprintf("<b>%s</b>: %s (%s), %d %s", group, humanize.Bytes(size),
+			utils.PercentString(float64(size)/float64(r.Size)), count, unit)
+		MarkdownDetails(sb, summary, t)
+	}
+
+	if options.Generics && len(r.Generics) > 0 {
+		var size uint64
+		for _, g := range r.Generics {
+			size += g.Size
+		}
+		summary := fmt.Sprintf("<b>generics</b>: %s (%s), %d functions", humanize.Bytes(size),
+			utils.PercentString(float64(size)/float64(r.Size)), len(r.Generics))
+		MarkdownDetails(sb, summary, genericsTable(r.Generics, MarkdownCode))
+	}
+
+	if len(r.Inlines) > 0 {
+		size := inlinesSize(r.Inlines)
+		summary := fmt.Sprintf("<b>inlined</b>: %s (%s), %d functions", humanize.Bytes(size),
+			utils.PercentString(float64(size)/float64(r.Size)), len(r.Inlines))
+		MarkdownDetails(sb, summary, inlinesTable(r.Inlines, MarkdownCode))
+	}
+
+	slog.Info("Report rendered")
+
+	_, err := writer.Write([]byte(sb.String()))
+
+	slog.Info("Report written")
+
+	return err
+}
@@ -0,0 +1,108 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func TestMarkdown(t *testing.T) {
+	const (
+		header = "## `app`\n\n" +
+			"- **Total:** 8.2 kB\n"
+		main = `<details>
+<summary><b>main</b>: 427 B (5.21%), 1 packages</summary>
+
+| Percent | Name | Size |
+| --- | --- | --- |
+| 5.21% | ` + "`main`" + ` | 427 B |
+
+</details>
+
+`
+		std = `<details>
+<summary><b>std</b>: 440 B (5.37%), 1 packages</summary>
+
+| Percent | Name | Size |
+| --- | --- | --- |
+| 5.37% | ` + "`runtime`" + ` | 440 B |
+
+</details>
+
+`
+		vendor = `<details>
+<summary><b>vendor</b>: 350 B (4.27%), 2 packages</summary>
+
+| Percent | Name | Size |
+| --- | --- | --- |
+| 4.03% | ` + "`golang.org/x/net`" + ` | 330 B |
+| 0.24% | ` + "`example.com/a\\|b`" + ` | 20 B |
+
+</details>
+
+`
+		generated = `<details>
+<summary><b>generated</b>: 55 B (0.67%), 1 packages</summary>
+
+| Percent | Name | Size |
+| --- | --- | --- |
+| 0.67% | ` + "`<autogenerated>`" + ` | 55 B |
+
+</details>
+
+`
+		sections = `<details>
+<summary><b>section</b>: 96 B (1.17%), 1 sections</summary>
+
+| Percent | Name | Size |
+| --- | --- | --- |
+| 1.17% | ` + "`.text`" + ` | 96 B |
+
+</details>
+
+`
+	)
+
+	tests := []struct {
+		name    string
+		options CommonOption
+		want    string
+	}{
+		{
+			name:    "all",
+			options: CommonOption{},
+			want:    header + "- **Known:** 1.4 kB (16.70%)\n\n" + main + std + vendor + generated + sections,
+		},
+		{
+			name:    "hide std and sections",
+			options: CommonOption{HideStd: true, HideSections: true},
+			want:    header + "- **Known:** 832 B (10.16%)\n\n" + main + vendor + generated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestResult()
+			// the pipe would end the cell
+			pipe := newTestPackage("example.com/a|b", entity.PackageTypeVendor)
+			pipe.Size = 20
+			r.Packages[pipe.Name] = pipe
+
+			var buf bytes.Buffer
+			require.NoError(t, Markdown(r, &buf, &tt.options))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	assert.Equal(t, "`*T.M`", MarkdownCode("*T.M"))
+	assert.Equal(t, "``a`b``", MarkdownCode("a`b"))
+	assert.Equal(t, "`` `a ``", MarkdownCode("`a"))
+}
@@ -0,0 +1,36 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func packageDisplayName(name string) string {
+	if name == "" {
+		return "<autogenerated>"
+	}
+	return name
+}
+
+// qualifiedFunctionName returns the function name as the linker sees it, methods are qualified by receiver.
+func qualifiedFunctionName(pkg string, fn *entity.Function) string {
+	name := fn.Name
+	if fn.Type == entity.FuncTypeMethod {
+		name = fn.Receiver + "." + fn.Name
+	}
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// findSection returns the section containing addr, and whether it takes space in the file.
+func findSection(sections []*entity.Section, addr uint64) (*entity.Section, bool) {
+	for _, s := range sections {
+		if !s.Debug && s.Addr <= addr && addr < s.AddrEnd {
+			return s, !s.OnlyInMemory
+		}
+	}
+	return nil, false
+}
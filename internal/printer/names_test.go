@@ -0,0 +1,115 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+)
+
+func newTestPackage(name string, typ entity.PackageType) *entity.Package {
+	p := entity.NewPackage()
+	p.Name = name
+	p.Type = typ
+	return p
+}
+
+func newTestFunction(name string, addr, codeSize, pclnSize uint64) *entity.Function {
+	return &entity.Function{
+		Name:     name,
+		Addr:     addr,
+		CodeSize: codeSize,
+		Type:     entity.FuncTypeFunction,
+		PclnSize: entity.PclnSymbolSize{Header: pclnSize},
+	}
+}
+
+// newTestResult builds a small result covering the shapes the printers handle:
+// methods with folded wrappers, sub packages, the generated package, data and
+// bss symbols and a section with unknown bytes.
+func newTestResult() *result.Result {
+	run := newTestFunction("Run", 0x1100, 200, 20)
+	run.Type = entity.FuncTypeMethod
+	run.Receiver = "*T"
+	run.Wrappers = []*entity.Function{newTestFunction("(*T).Run-fm", 0x1200, 30, 3)}
+
+	mainPkg := newTestPackage("main", entity.PackageTypeMain)
+	mainPkg.Files = []*entity.File{{
+		FilePath:  "/src/app/main.go",
+		Functions: []*entity.Function{newTestFunction("main", 0x1000, 100, 10), run},
+	}}
+	mainPkg.Symbols = []*entity.Symbol{
+		{Name: "main.table", Addr: 0x3000, Size: 64, Type: entity.AddrTypeData},
+		{Name: "main.buf", Addr: 0x4000, Size: 512, Type: entity.AddrTypeData},
+	}
+	mainPkg.Size = 100 + 10 + 200 + 20 + 30 + 3 + 64
+
+	http2 := newTestPackage("golang.org/x/net/http2", entity.PackageTypeVendor)
+	http2.Files = []*entity.File{{
+		FilePath:  "/mod/golang.org/x/net/http2/frame.go",
+		Functions: []*entity.Function{newTestFunction("readFrame", 0x1300, 300, 30)},
+	}}
+	http2.Size = 330
+
+	net := newTestPackage("golang.org/x/net", entity.PackageTypeVendor)
+	net.SubPackages["http2"] = http2
+	net.Size = 330
+
+	runtime := newTestPackage("runtime", entity.PackageTypeStd)
+	runtime.Files = []*entity.File{{
+		FilePath:  "/goroot/src/runtime/proc.go",
+		Functions: []*entity.Function{newTestFunction("schedule", 0x1400, 400, 40)},
+	}}
+	runtime.Size = 440
+
+	generated := newTestPackage("", entity.PackageTypeGenerated)
+	generated.Files = []*entity.File{{
+		FilePath:  "<autogenerated>",
+		Functions: []*entity.Function{newTestFunction("type:.eq.[2]string", 0x1500, 50, 5)},
+	}}
+	generated.Size = 55
+
+	return &result.Result{
+		Name: "app",
+		Size: 0x2000,
+		Packages: entity.PackageMap{
+			"main":             mainPkg,
+			"golang.org/x/net": net,
+			"runtime":          runtime,
+			"":                 generated,
+		},
+		Sections: []*entity.Section{
+			{Name: ".text", Addr: 0x1000, AddrEnd: 0x2000, Size: 0x1000, FileSize: 0x1000, KnownSize: 0x1000 - 96},
+			{Name: ".data", Addr: 0x3000, AddrEnd: 0x3100, Size: 0x100, FileSize: 0x100, KnownSize: 0x100},
+			{Name: ".bss", Addr: 0x4000, AddrEnd: 0x5000, Size: 0x1000, OnlyInMemory: true},
+		},
+	}
+}
+
+func TestQualifiedFunctionName(t *testing.T) {
+	r := newTestResult()
+	fns := r.Packages["main"].Files[0].Functions
+
+	assert.Equal(t, "main.main", qualifiedFunctionName("main", fns[0]))
+	assert.Equal(t, "main.*T.Run", qualifiedFunctionName("main", fns[1]))
+	assert.Equal(t, "type:.eq.[2]string", qualifiedFunctionName("", r.Packages[""].Files[0].Functions[0]))
+}
+
+func TestFindSection(t *testing.T) {
+	sections := newTestResult().Sections
+
+	s, inFile := findSection(sections, 0x1800)
+	assert.Equal(t, ".text", s.Name)
+	assert.True(t, inFile)
+
+	s, inFile = findSection(sections, 0x4000)
+	assert.Equal(t, ".bss", s.Name)
+	assert.False(t, inFile)
+
+	s, _ = findSection(sections, 0x2000)
+	assert.Nil(t, s)
+}
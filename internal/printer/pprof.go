@@ -0,0 +1,344 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+)
+
+// protobuf is a minimal encoder for profile.proto, see
+// https://github.com/google/pprof/blob/main/proto/profile.proto
+type protobuf struct {
+	data []byte
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protobuf) key(tag, wire int) {
+	b.varint(uint64(tag)<<3 | uint64(wire))
+}
+
+func (b *protobuf) uint64(tag int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.key(tag, wireVarint)
+	b.varint(x)
+}
+
+func (b *protobuf) bytes(tag int, x []byte) {
+	b.key(tag, wireBytes)
+	b.varint(uint64(len(x)))
+	b.data = append(b.data, x...)
+}
+
+func (b *protobuf) packed(tag int, xs []uint64) {
+	p := new(protobuf)
+	for _, x := range xs {
+		p.varint(x)
+	}
+	b.bytes(tag, p.data)
+}
+
+func (b *protobuf) message(tag int, fn func(m *protobuf)) {
+	m := new(protobuf)
+	fn(m)
+	b.bytes(tag, m.data)
+}
+
+// field numbers of profile.proto
+const (
+	tagProfileSampleType        = 1
+	tagProfileSample            = 2
+	tagProfileMapping           = 3
+	tagProfileLocation          = 4
+	tagProfileFunction          = 5
+	tagProfileStringTable       = 6
+	tagProfilePeriodType        = 11
+	tagProfilePeriod            = 12
+	tagProfileDefaultSampleType = 14
+
+	tagValueTypeType = 1
+	tagValueTypeUnit = 2
+
+	tagSampleLocation = 1
+	tagSampleValue    = 2
+
+	tagMappingID           = 1
+	tagMappingFilename     = 5
+	tagMappingHasFunctions = 7
+	tagMappingHasFilenames = 8
+
+	tagLocationID      = 1
+	tagLocationMapping = 2
+	tagLocationLine    = 4
+
+	tagLineFunction = 1
+
+	tagFunctionID       = 1
+	tagFunctionName     = 2
+	tagFunctionSysName  = 3
+	tagFunctionFilename = 4
+)
+
+// pprofSampleTypes are the values of every sample, size is the sum of the others
+// plus the unknown part of sections.
+var pprofSampleTypes = []string{"size", "code", "pcln", "data"}
+
+const (
+	pprofValueSize = iota
+	pprofValueCode
+	pprofValuePcln
+	pprofValueData
+)
+
+type pprofFrame struct {
+	name     string
+	filename string
+}
+
+type pprofSample struct {
+	stack  []uint64 // location ids, leaf first
+	values []uint64
+}
+
+type pprofBuilder struct {
+	strings   []string
+	stringIDs map[string]uint64
+
+	frames   []pprofFrame
+	frameIDs map[pprofFrame]uint64
+
+	samples []pprofSample
+}
+
+func (b *pprofBuilder) str(s string) uint64 {
+	if id, ok := b.stringIDs[s]; ok {
+		return id
+	}
+	id := uint64(len(b.strings))
+	b.strings = append(b.strings, s)
+	b.stringIDs[s] = id
+	return id
+}
+
+// frame returns the location id of f, a location and its function share the same id.
+func (b *pprofBuilder) frame(f pprofFrame) uint64 {
+	if id, ok := b.frameIDs[f]; ok {
+		return id
+	}
+	b.frames = append(b.frames, f)
+	id := uint64(len(b.frames))
+	b.frameIDs[f] = id
+	return id
+}
+
+// add records a sample, stack is root first.
+func (b *pprofBuilder) add(stack []pprofFrame, values []uint64) {
+	ids := make([]uint64, len(stack))
+	for i, f := range stack {
+		ids[len(stack)-1-i] = b.frame(f)
+	}
+	b.samples = append(b.samples, pprofSample{stack: ids, values: values})
+}
+
+func (b *pprofBuilder) encode(binary string) []byte {
+	p := new(protobuf)
+
+	// intern all strings before the string table is written
+	bytesID := b.str("bytes")
+	typeIDs := make([]uint64, len(pprofSampleTypes))
+	for i, t := range pprofSampleTypes {
+		typeIDs[i] = b.str(t)
+	}
+	binaryID := b.str(binary)
+	type frameStrings struct{ name, filename uint64 }
+	frameStrs := make([]frameStrings, len(b.frames))
+	for i, f := range b.frames {
+		frameStrs[i] = frameStrings{b.str(f.name), b.str(f.filename)}
+	}
+
+	for _, id := range typeIDs {
+		p.message(tagProfileSampleType, func(m *protobuf) {
+			m.uint64(tagValueTypeType, id)
+			m.uint64(tagValueTypeUnit, bytesID)
+		})
+	}
+
+	for _, s := range b.samples {
+		p.message(tagProfileSample, func(m *protobuf) {
+			m.packed(tagSampleLocation, s.stack)
+			m.packed(tagSampleValue, s.values)
+		})
+	}
+
+	// sizes are already symbolized, keep pprof from looking for the binary
+	const mappingID = 1
+	p.message(tagProfileMapping, func(m *protobuf) {
+		m.uint64(tagMappingID, mappingID)
+		m.uint64(tagMappingFilename, binaryID)
+		m.uint64(tagMappingHasFunctions, 1)
+		m.uint64(tagMappingHasFilenames, 1)
+	})
+
+	for i := range b.frames {
+		id := uint64(i + 1)
+		p.message(tagProfileLocation, func(m *protobuf) {
+			m.uint64(tagLocationID, id)
+			m.uint64(tagLocationMapping, mappingID)
+			m.message(tagLocationLine, func(l *protobuf) {
+				l.uint64(tagLineFunction, id)
+			})
+		})
+	}
+
+	for i, s := range frameStrs {
+		p.message(tagProfileFunction, func(m *protobuf) {
+			m.uint64(tagFunctionID, uint64(i+1))
+			m.uint64(tagFunctionName, s.name)
+			m.uint64(tagFunctionSysName, s.name)
+			m.uint64(tagFunctionFilename, s.filename)
+		})
+	}
+
+	for _, s := range b.strings {
+		p.bytes(tagProfileStringTable, []byte(s))
+	}
+
+	p.message(tagProfilePeriodType, func(m *protobuf) {
+		m.uint64(tagValueTypeType, typeIDs[pprofValueSize])
+		m.uint64(tagValueTypeUnit, bytesID)
+	})
+	p.uint64(tagProfilePeriod, 1)
+	p.uint64(tagProfileDefaultSampleType, typeIDs[pprofValueSize])
+
+	return p.data
+}
+
+// packagePathParts splits the import path of a package below parent into its
+// components, a sub package only adds the components after the parent path.
+func packagePathParts(name, parent string) []string {
+	if name == "" {
+		return []string{packageDisplayName(name)}
+	}
+	if parent != "" && strings.HasPrefix(name, parent+"/") {
+		name = name[len(parent)+1:]
+	}
+	return strings.Split(name, "/")
+}
+
+// Pprof encodes the result as a gzipped profile.proto, the stack of a sample is
+// section, package path components, file and function, the values are bytes.
+func Pprof(r *result.Result, writer io.Writer) error {
+	slog.Info("Printing pprof profile")
+
+	b := &pprofBuilder{
+		strings:   []string{""},
+		stringIDs: map[string]uint64{"": 0},
+		frameIDs:  make(map[pprofFrame]uint64),
+	}
+
+	const unknownSection = "<unknown section>"
+
+	sectionFrame := func(addr uint64) (pprofFrame, bool) {
+		s, ok := findSection(r.Sections, addr)
+		if s == nil {
+			return pprofFrame{name: unknownSection}, true
+		}
+		return pprofFrame{name: s.Name}, ok
+	}
+
+	values := func(idx int, v uint64) []uint64 {
+		ret := make([]uint64, len(pprofSampleTypes))
+		ret[pprofValueSize] = v
+		ret[idx] = v
+		return ret
+	}
+
+	// the wrappers folded into a function are frames below it
+	var addFunction func(pkg string, f *entity.File, stack []pprofFrame, fn *entity.Function)
+	addFunction = func(pkg string, f *entity.File, stack []pprofFrame, fn *entity.Function) {
+		stack = append(stack, pprofFrame{name: qualifiedFunctionName(pkg, fn), filename: f.FilePath})
+
+		v := make([]uint64, len(pprofSampleTypes))
+		v[pprofValueCode] = fn.CodeSize
+		v[pprofValuePcln] = fn.PclnSize.Size()
+		v[pprofValueSize] = v[pprofValueCode] + v[pprofValuePcln]
+		b.add(stack, v)
+
+		for _, w := range fn.Wrappers {
+			addFunction(pkg, f, stack, w)
+		}
+	}
+
+	var walk func(p *entity.Package, parent string, pkgStack []pprofFrame)
+	walk = func(p *entity.Package, parent string, pkgStack []pprofFrame) {
+		for _, part := range packagePathParts(p.Name, parent) {
+			pkgStack = append(pkgStack, pprofFrame{name: part})
+		}
+
+		for _, f := range p.Files {
+			fileFrame := pprofFrame{name: f.FilePath, filename: f.FilePath}
+			for _, fn := range f.Functions {
+				sect, _ := sectionFrame(fn.Addr)
+				addFunction(p.Name, f, append(append([]pprofFrame{sect}, pkgStack...), fileFrame), fn)
+			}
+		}
+
+		for _, s := range p.Symbols {
+			if s.Type != entity.AddrTypeData {
+				continue
+			}
+			sect, inFile := sectionFrame(s.Addr)
+			if !inFile {
+				// bss like, takes no space in the file
+				continue
+			}
+			stack := append(append([]pprofFrame{sect}, pkgStack...), pprofFrame{name: s.Name})
+			b.add(stack, values(pprofValueData, s.Size))
+		}
+
+		for _, k := range utils.SortedKeys(p.SubPackages) {
+			walk(p.SubPackages[k], p.Name, pkgStack)
+		}
+	}
+
+	for _, k := range utils.SortedKeys(r.Packages) {
+		walk(r.Packages[k], "", nil)
+	}
+
+	for _, s := range r.Sections {
+		if s.OnlyInMemory || s.FileSize <= s.KnownSize {
+			continue
+		}
+		b.add([]pprofFrame{{name: s.Name}}, values(pprofValueSize, s.FileSize-s.KnownSize))
+	}
+
+	gz := gzip.NewWriter(writer)
+	if _, err := gz.Write(b.encode(r.Name)); err != nil {
+		return err
+	}
+	err := gz.Close()
+
+	slog.Info("Profile written")
+
+	return err
+}
@@ -0,0 +1,241 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// protoField is a decoded protobuf field, val holds varints and data the bytes.
+type protoField struct {
+	tag  int
+	val  uint64
+	data []byte
+}
+
+func decodeProto(t *testing.T, b []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		require.Positive(t, n, "bad key")
+		b = b[n:]
+		f := protoField{tag: int(key >> 3)}
+		switch key & 7 {
+		case wireVarint:
+			f.val, n = binary.Uvarint(b)
+			require.Positive(t, n, "bad varint of field %d", f.tag)
+			b = b[n:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			require.Positive(t, n, "bad length of field %d", f.tag)
+			b = b[n:]
+			require.LessOrEqual(t, l, uint64(len(b)), "field %d overflows", f.tag)
+			f.data, b = b[:l], b[l:]
+		default:
+			require.Failf(t, "unexpected wire type", "field %d has wire type %d", f.tag, key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func decodePacked(t *testing.T, b []byte) []uint64 {
+	t.Helper()
+	var ret []uint64
+	for len(b) > 0 {
+		x, n := binary.Uvarint(b)
+		require.Positive(t, n)
+		ret = append(ret, x)
+		b = b[n:]
+	}
+	return ret
+}
+
+// fieldValue returns the varint of tag in m, zero when absent like proto3.
+func fieldValue(t *testing.T, m []byte, tag int) uint64 {
+	t.Helper()
+	for _, f := range decodeProto(t, m) {
+		if f.tag == tag {
+			return f.val
+		}
+	}
+	return 0
+}
+
+type testProfile struct {
+	strings           []string
+	sampleTypes       [][2]string // type and unit
+	defaultSampleType string
+	// samples maps the ";" joined stack, root first, to the values
+	samples map[string][]uint64
+}
+
+func parseProfile(t *testing.T, gz []byte) testProfile {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	require.NoError(t, err)
+	raw, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	fields := decodeProto(t, raw)
+
+	p := testProfile{samples: make(map[string][]uint64)}
+	for _, f := range fields {
+		if f.tag == tagProfileStringTable {
+			p.strings = append(p.strings, string(f.data))
+		}
+	}
+	require.NotEmpty(t, p.strings)
+	require.Empty(t, p.strings[0], "the first string must be empty")
+	str := func(id uint64) string {
+		require.Less(t, id, uint64(len(p.strings)))
+		return p.strings[id]
+	}
+
+	functions := make(map[uint64]string)
+	locations := make(map[uint64]uint64)
+	var mappings int
+	for _, f := range fields {
+		switch f.tag {
+		case tagProfileSampleType:
+			p.sampleTypes = append(p.sampleTypes, [2]string{
+				str(fieldValue(t, f.data, tagValueTypeType)),
+				str(fieldValue(t, f.data, tagValueTypeUnit)),
+			})
+		case tagProfileDefaultSampleType:
+			p.defaultSampleType = str(f.val)
+		case tagProfileMapping:
+			mappings++
+			assert.Equal(t, uint64(1), fieldValue(t, f.data, tagMappingID))
+			assert.Equal(t, "app", str(fieldValue(t, f.data, tagMappingFilename)))
+		case tagProfileFunction:
+			id := fieldValue(t, f.data, tagFunctionID)
+			require.NotZero(t, id)
+			require.NotContains(t, functions, id, "duplicate function id")
+			name := str(fieldValue(t, f.data, tagFunctionName))
+			assert.Equal(t, name, str(fieldValue(t, f.data, tagFunctionSysName)))
+			functions[id] = name
+		case tagProfileLocation:
+			var id, line uint64
+			for _, lf := range decodeProto(t, f.data) {
+				switch lf.tag {
+				case tagLocationID:
+					id = lf.val
+				case tagLocationMapping:
+					assert.Equal(t, uint64(1), lf.val)
+				case tagLocationLine:
+					line = fieldValue(t, lf.data, tagLineFunction)
+				}
+			}
+			require.NotZero(t, id)
+			require.NotContains(t, locations, id, "duplicate location id")
+			locations[id] = line
+		}
+	}
+	assert.Equal(t, 1, mappings)
+
+	for _, f := range fields {
+		if f.tag != tagProfileSample {
+			continue
+		}
+		var stack, values []uint64
+		for _, sf := range decodeProto(t, f.data) {
+			switch sf.tag {
+			case tagSampleLocation:
+				stack = decodePacked(t, sf.data)
+			case tagSampleValue:
+				values = decodePacked(t, sf.data)
+			}
+		}
+		require.Len(t, values, len(p.sampleTypes))
+
+		names := make([]string, len(stack))
+		for i, loc := range stack {
+			fn, ok := locations[loc]
+			require.True(t, ok, "unknown location %d", loc)
+			name, ok := functions[fn]
+			require.True(t, ok, "unknown function %d", fn)
+			// stacks are leaf first
+			names[len(stack)-1-i] = name
+		}
+		key := strings.Join(names, ";")
+		require.NotContains(t, p.samples, key, "duplicate stack")
+		p.samples[key] = values
+	}
+
+	return p
+}
+
+func TestPprof(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Pprof(newTestResult(), &buf))
+
+	p := parseProfile(t, buf.Bytes())
+
+	assert.Equal(t, [][2]string{
+		{"size", "bytes"},
+		{"code", "bytes"},
+		{"pcln", "bytes"},
+		{"data", "bytes"},
+	}, p.sampleTypes)
+	assert.Equal(t, "size", p.defaultSampleType)
+
+	assert.Equal(t, map[string][]uint64{
+		".text;<autogenerated>;<autogenerated>;type:.eq.[2]string":                                           {55, 50, 5, 0},
+		".text;golang.org;x;net;http2;/mod/golang.org/x/net/http2/frame.go;golang.org/x/net/http2.readFrame": {330, 300, 30, 0},
+		".text;main;/src/app/main.go;main.main":                                                              {110, 100, 10, 0},
+		".text;main;/src/app/main.go;main.*T.Run":                                                            {220, 200, 20, 0},
+		".text;main;/src/app/main.go;main.*T.Run;main.(*T).Run-fm":                                           {33, 30, 3, 0},
+		".data;main;main.table":                                                                              {64, 0, 0, 64},
+		".text;runtime;/goroot/src/runtime/proc.go;runtime.schedule":                                         {440, 400, 40, 0},
+		".text": {96, 0, 0, 0},
+	}, p.samples)
+}
+
+func TestPprofSharesFrames(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Pprof(newTestResult(), &buf))
+
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	var locations, functions int
+	seen := make(map[string]int)
+	for _, f := range decodeProto(t, raw) {
+		switch f.tag {
+		case tagProfileLocation:
+			locations++
+		case tagProfileFunction:
+			functions++
+		case tagProfileStringTable:
+			seen[string(f.data)]++
+		}
+	}
+
+	// every frame is both a location and a function, and written once
+	assert.Equal(t, locations, functions)
+	assert.Equal(t, 20, functions)
+	for s, n := range seen {
+		assert.Equal(t, 1, n, "string %q is duplicated", s)
+	}
+}
+
+func TestPackagePathParts(t *testing.T) {
+	assert.Equal(t, []string{"<autogenerated>"}, packagePathParts("", ""))
+	assert.Equal(t, []string{"main"}, packagePathParts("main", ""))
+	assert.Equal(t, []string{"golang.org", "x", "net"}, packagePathParts("golang.org/x/net", ""))
+	assert.Equal(t, []string{"http2"}, packagePathParts("golang.org/x/net/http2", "golang.org/x/net"))
+	assert.Equal(t, []string{"example.com", "b"}, packagePathParts("example.com/b", "example.com/a"))
+}
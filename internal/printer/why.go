@@ -0,0 +1,40 @@
+//go:build !js && !wasm
+
+package printer
+
+import (
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/Zxilly/go-size-analyzer/internal/utils"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
+)
+
+// whyTable renders the import chains of w, one row per package.
+func whyTable(w *why.Result) string {
+	t := table.NewWriter()
+	t.SetStyle(utils.GetTableStyle())
+
+	t.SetTitle("Why %s", w.Target)
+	t.AppendHeader(table.Row{"Chain", "Package", "Size"})
+
+	if len(w.Chains) == 0 {
+		t.AppendRow(table.Row{"", "no import chain from " + why.Root + " found", ""})
+	}
+	for i, chain := range w.Chains {
+		if i > 0 {
+			t.AppendSeparator()
+		}
+		for j, l := range chain {
+			n := ""
+			if j == 0 {
+				n = strconv.Itoa(i + 1)
+			}
+			t.AppendRow(table.Row{n, packageDisplayName(l.Package), humanize.Bytes(l.Size)})
+		}
+	}
+
+	return t.Render() + "\n"
+}
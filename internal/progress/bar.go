@@ -0,0 +1,84 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const barWidth = 30
+
+// Bar draws the analysis progress on the last line of a terminal,
+// lines written through it are printed above the bar.
+type Bar struct {
+	mu   sync.Mutex
+	out  io.Writer
+	line string
+}
+
+func NewBar(out io.Writer) *Bar {
+	return &Bar{out: out}
+}
+
+func (b *Bar) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clear()
+	n, err := b.out.Write(p)
+	b.draw()
+	return n, err
+}
+
+// Update redraws the bar with e, it is a Func.
+// The bar is removed as the coverage phase starts, which is the last and
+// short one, so it is gone before the result is printed.
+func (b *Bar) Update(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clear()
+	b.line = ""
+	if e.Phase != PhaseCoverage {
+		b.line = formatEvent(e)
+	}
+	b.draw()
+}
+
+// Done removes the bar.
+func (b *Bar) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clear()
+	b.line = ""
+}
+
+func (b *Bar) clear() {
+	if b.line != "" {
+		_, _ = io.WriteString(b.out, "\r\x1b[K")
+	}
+}
+
+func (b *Bar) draw() {
+	if b.line != "" {
+		_, _ = io.WriteString(b.out, b.line)
+	}
+}
+
+func formatEvent(e Event) string {
+	switch {
+	case e.Total > 0:
+		filled := min(e.Done*barWidth/e.Total, barWidth)
+		return fmt.Sprintf("%-12s [%s%s] %d/%d",
+			e.Phase,
+			strings.Repeat("=", filled),
+			strings.Repeat(" ", barWidth-filled),
+			e.Done, e.Total)
+	case e.Done > 0:
+		return fmt.Sprintf("%-12s %d", e.Phase, e.Done)
+	default:
+		return string(e.Phase)
+	}
+}
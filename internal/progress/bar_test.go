@@ -0,0 +1,79 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"phase only", Event{Phase: PhaseParse}, "parse"},
+		{"count", Event{Phase: PhaseSymbol, Done: 42}, "symbol       42"},
+		{"empty bar", Event{Phase: PhaseDisasm, Total: 10},
+			"disasm       [                              ] 0/10"},
+		{"half bar", Event{Phase: PhaseDisasm, Done: 5, Total: 10},
+			"disasm       [===============               ] 5/10"},
+		{"full bar", Event{Phase: PhaseDisasm, Done: 10, Total: 10},
+			"disasm       [==============================] 10/10"},
+		{"overflow", Event{Phase: PhaseDisasm, Done: 12, Total: 10},
+			"disasm       [==============================] 12/10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatEvent(tt.event))
+		})
+	}
+}
+
+func TestBar(t *testing.T) {
+	const clear = "\r\x1b[K"
+
+	var out bytes.Buffer
+	bar := NewBar(&out)
+
+	// nothing to clear without a bar
+	n, err := bar.Write([]byte("log 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+	assert.Equal(t, "log 1\n", out.String())
+
+	out.Reset()
+	bar.Update(Event{Phase: PhaseParse})
+	assert.Equal(t, "parse", out.String())
+
+	// a line is printed above the bar, which is drawn again
+	out.Reset()
+	_, err = bar.Write([]byte("log 2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, clear+"log 2\n"+"parse", out.String())
+
+	out.Reset()
+	bar.Update(Event{Phase: PhaseSymbol, Done: 3})
+	assert.Equal(t, clear+"symbol       3", out.String())
+
+	// the coverage phase removes the bar
+	out.Reset()
+	bar.Update(Event{Phase: PhaseCoverage})
+	assert.Equal(t, clear, out.String())
+
+	out.Reset()
+	_, err = bar.Write([]byte("log 3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "log 3\n", out.String())
+
+	out.Reset()
+	bar.Update(Event{Phase: PhaseDisasm, Done: 1, Total: 2})
+	bar.Done()
+	assert.Equal(t, "disasm       [===============               ] 1/2"+clear, out.String())
+
+	out.Reset()
+	bar.Done()
+	assert.Empty(t, out.String())
+}
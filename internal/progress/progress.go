@@ -0,0 +1,76 @@
+package progress
+
+import "sync"
+
+// Phase is a step of the analysis pipeline, in the order they run.
+type Phase string
+
+const (
+	PhaseParse       Phase = "parse"
+	PhaseSections    Phase = "sections"
+	PhasePackages    Phase = "packages"
+	PhaseDwarf       Phase = "dwarf"
+	PhaseSymbol      Phase = "symbol"
+	PhaseTypes       Phase = "types"
+	PhasePclntabMeta Phase = "pclntab_meta"
+	PhaseDisasm      Phase = "disasm"
+	PhaseImports     Phase = "imports"
+	PhaseCoverage    Phase = "coverage"
+)
+
+// Event is sent when a phase starts, with Done and Total being zero,
+// then as the items of the phase are processed.
+// Total is zero if the number of items is not known in advance.
+type Event struct {
+	Phase Phase
+	Done  int
+	Total int
+}
+
+// Func receives progress events. It may be called from worker goroutines,
+// but never concurrently.
+type Func func(Event)
+
+// Report sends e to f, a nil Func discards it.
+func (f Func) Report(e Event) {
+	if f != nil {
+		f(e)
+	}
+}
+
+// Counter counts the items done of a phase and reports about every
+// percent of them, it is safe for concurrent use.
+type Counter struct {
+	mu    sync.Mutex
+	f     Func
+	phase Phase
+	done  int
+	total int
+	step  int
+}
+
+// NewCounter returns a Counter of total items, total is zero if unknown.
+func NewCounter(f Func, phase Phase, total int) *Counter {
+	return &Counter{
+		f:     f,
+		phase: phase,
+		total: total,
+		step:  max(total/100, 1),
+	}
+}
+
+// Add marks n more items done.
+func (c *Counter) Add(n int) {
+	if c.f == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	before := c.done
+	c.done += n
+	if c.done/c.step != before/c.step || c.done == c.total {
+		c.f(Event{Phase: c.phase, Done: c.done, Total: c.total})
+	}
+}
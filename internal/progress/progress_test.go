@@ -0,0 +1,78 @@
+package progress
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilFunc(t *testing.T) {
+	var f Func
+	assert.NotPanics(t, func() {
+		f.Report(Event{Phase: PhaseParse})
+		NewCounter(f, PhaseDisasm, 10).Add(1)
+	})
+}
+
+func TestCounterReportsEachPercent(t *testing.T) {
+	var events []Event
+	c := NewCounter(func(e Event) {
+		events = append(events, e)
+	}, PhaseDisasm, 1000)
+
+	for range 1000 {
+		c.Add(1)
+	}
+
+	assert.Len(t, events, 100)
+	assert.Equal(t, Event{Phase: PhaseDisasm, Done: 10, Total: 1000}, events[0])
+	assert.Equal(t, Event{Phase: PhaseDisasm, Done: 1000, Total: 1000}, events[99])
+}
+
+func TestCounterReportsCompletion(t *testing.T) {
+	var events []Event
+	c := NewCounter(func(e Event) {
+		events = append(events, e)
+	}, PhaseImports, 3)
+
+	c.Add(1)
+	c.Add(2)
+
+	assert.Equal(t, []Event{
+		{Phase: PhaseImports, Done: 1, Total: 3},
+		{Phase: PhaseImports, Done: 3, Total: 3},
+	}, events)
+}
+
+func TestCounterUnknownTotal(t *testing.T) {
+	var events []Event
+	c := NewCounter(func(e Event) {
+		events = append(events, e)
+	}, PhaseDwarf, 0)
+
+	c.Add(1)
+	c.Add(1)
+
+	assert.Equal(t, []Event{
+		{Phase: PhaseDwarf, Done: 1},
+		{Phase: PhaseDwarf, Done: 2},
+	}, events)
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	var last Event
+	c := NewCounter(func(e Event) {
+		last = e
+	}, PhaseDisasm, 500)
+
+	var wg sync.WaitGroup
+	for range 500 {
+		wg.Go(func() {
+			c.Add(1)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, Event{Phase: PhaseDisasm, Done: 500, Total: 500}, last)
+}
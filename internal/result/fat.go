@@ -0,0 +1,18 @@
+package result
+
+// Fat is the result of every slice of a universal Mach-O binary.
+type Fat struct {
+	Name string `json:"name"`
+	Size uint64 `json:"size"`
+
+	Slices []*Slice `json:"slices"`
+}
+
+// Slice is the result of the Mach-O file of an architecture in a universal binary.
+type Slice struct {
+	Arch   string `json:"arch"`
+	Offset uint64 `json:"offset"`
+	Size   uint64 `json:"size"`
+
+	Result *Result `json:"result"`
+}
@@ -0,0 +1,51 @@
+package result
+
+import (
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// ModuleTree returns a copy of r whose top level packages are the modules,
+// each one holds the packages it owns, without their sub packages.
+func (r *Result) ModuleTree() *Result {
+	byName := make(map[string]*entity.Package)
+	var walk func(pkgs entity.PackageMap)
+	walk = func(pkgs entity.PackageMap) {
+		for _, p := range pkgs {
+			byName[p.Name] = p
+			walk(p.SubPackages)
+		}
+	}
+	walk(r.Packages)
+
+	packages := make(entity.PackageMap, len(r.Modules))
+	for _, m := range r.Modules {
+		node := entity.NewPackage()
+		node.Name = strings.TrimSpace(m.Path + " " + m.VersionString())
+		node.Type = m.Type
+		node.Size = m.Size
+
+		for _, name := range m.Packages {
+			p, ok := byName[name]
+			if !ok {
+				continue
+			}
+			own := entity.NewPackage()
+			own.Name = p.Name
+			own.Type = p.Type
+			own.Size = p.OwnSize()
+			own.Files = p.Files
+			own.Symbols = p.Symbols
+			own.ImportedBy = p.ImportedBy
+			own.Retained = p.Retained
+			node.SubPackages[name] = own
+		}
+
+		packages[m.Path] = node
+	}
+
+	ret := *r
+	ret.Packages = packages
+	return &ret
+}
@@ -0,0 +1,56 @@
+package result_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+)
+
+func TestModuleTree(t *testing.T) {
+	sub := entity.NewPackage()
+	sub.Name = "example.com/lib/sub"
+	sub.Type = entity.PackageTypeVendor
+	sub.Size = 100
+
+	lib := entity.NewPackage()
+	lib.Name = "example.com/lib"
+	lib.Type = entity.PackageTypeVendor
+	lib.Size = 300
+	lib.Files = []*entity.File{{FilePath: "lib.go"}}
+	lib.SubPackages["sub"] = sub
+
+	r := &result.Result{
+		Name:     "bin",
+		Size:     1000,
+		Packages: entity.PackageMap{"example.com/lib": lib},
+		Modules: []*entity.Module{
+			{Path: "example.com/lib", Version: "v1.0.0", Type: entity.PackageTypeVendor,
+				Packages: []string{"example.com/lib"}, Size: 200},
+			{Path: "example.com/lib/sub", Version: "v0.1.0", Replace: "../sub", Type: entity.PackageTypeVendor,
+				Packages: []string{"example.com/lib/sub"}, Size: 100},
+		},
+	}
+
+	tree := r.ModuleTree()
+
+	assert.Equal(t, "bin", tree.Name)
+	assert.Same(t, lib, r.Packages["example.com/lib"], "the original result is unchanged")
+	require.Len(t, tree.Packages, 2)
+
+	m := tree.Packages["example.com/lib"]
+	assert.Equal(t, "example.com/lib v1.0.0", m.Name)
+	assert.Equal(t, uint64(200), m.Size)
+	require.Len(t, m.SubPackages, 1)
+	own := m.SubPackages["example.com/lib"]
+	assert.Equal(t, uint64(200), own.Size)
+	assert.Equal(t, lib.Files, own.Files)
+	assert.Empty(t, own.SubPackages)
+
+	m = tree.Packages["example.com/lib/sub"]
+	assert.Equal(t, "example.com/lib/sub => ../sub v0.1.0", m.Name)
+	assert.Equal(t, uint64(100), m.SubPackages["example.com/lib/sub"].Size)
+}
@@ -2,6 +2,7 @@ package result
 
 import (
 	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
 )
 
 type Result struct {
@@ -11,4 +12,16 @@ type Result struct {
 	Analyzers []entity.Analyzer `json:"analyzers"`
 	Packages  entity.PackageMap `json:"packages"`
 	Sections  []*entity.Section `json:"sections"`
+	// Modules and Generics are only filled with the modules and the generics.
+	Modules  []*entity.Module  `json:"modules,omitempty"`
+	Generics []*entity.Generic `json:"generics,omitempty"`
+
+	// Inlines is the code inlined from other packages, only filled with the inlines.
+	Inlines []*entity.Inline `json:"inlines,omitempty"`
+
+	// Imports is the import graph as an edge list, only filled with the imports.
+	Imports []why.Edge `json:"imports,omitempty"`
+
+	// Why is only filled on request, see why.Query.
+	Why []*why.Result `json:"why,omitempty"`
 }
@@ -2,6 +2,13 @@
 
 package result
 
+import (
+	"github.com/samber/lo"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
+)
+
 func (r *Result) MarshalJavaScript() any {
 	var sections []any
 	for _, s := range r.Sections {
@@ -12,13 +19,35 @@ func (r *Result) MarshalJavaScript() any {
 		analyzers = append(analyzers, a)
 	}
 
+	var modules []any
+	for _, m := range r.Modules {
+		modules = append(modules, m.MarshalJavaScript())
+	}
+
 	packages := r.Packages.MarshalJavaScript()
 
-	return map[string]any{
+	ret := map[string]any{
 		"name":      r.Name,
 		"size":      r.Size,
 		"packages":  packages,
 		"sections":  sections,
 		"analyzers": analyzers,
 	}
+	// omitted like the empty fields of the json output
+	if len(modules) > 0 {
+		ret["modules"] = modules
+	}
+	if len(r.Generics) > 0 {
+		ret["generics"] = lo.Map(r.Generics, func(g *entity.Generic, _ int) any { return g.MarshalJavaScript() })
+	}
+	if len(r.Inlines) > 0 {
+		ret["inlines"] = lo.Map(r.Inlines, func(i *entity.Inline, _ int) any { return i.MarshalJavaScript() })
+	}
+	if len(r.Imports) > 0 {
+		ret["imports"] = lo.Map(r.Imports, func(e why.Edge, _ int) any { return e.MarshalJavaScript() })
+	}
+	if len(r.Why) > 0 {
+		ret["why"] = lo.Map(r.Why, func(w *why.Result, _ int) any { return w.MarshalJavaScript() })
+	}
+	return ret
 }
@@ -14,8 +14,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
 	"github.com/Zxilly/go-size-analyzer/internal/entity/marshaler"
 	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
 )
 
 func TestResultMarshalJavaScriptCross(t *testing.T) {
@@ -29,6 +31,77 @@ func TestResultMarshalJavaScriptCross(t *testing.T) {
 	err = gob.NewDecoder(decompressedReader).Decode(r)
 	require.NoError(t, err)
 
+	assertMarshalJavaScriptCross(t, r)
+}
+
+func TestResultMarshalJavaScriptModules(t *testing.T) {
+	r := &result.Result{
+		Name:     "bin",
+		Size:     100,
+		Packages: entity.PackageMap{},
+		Modules: []*entity.Module{
+			{Path: "example.com/a", Version: "v1.0.0", Type: entity.PackageTypeVendor, Packages: []string{"example.com/a"}, Size: 10},
+			{Path: "example.com/b", Version: "v1.1.0", Replace: "example.com/c", Type: entity.PackageTypeVendor, Packages: []string{}, Size: 0},
+		},
+	}
+
+	assertMarshalJavaScriptCross(t, r)
+}
+
+func TestResultMarshalJavaScriptReports(t *testing.T) {
+	r := &result.Result{
+		Name:     "bin",
+		Size:     100,
+		Packages: entity.PackageMap{},
+		Generics: []*entity.Generic{{
+			Package: "example.com/list",
+			Name:    "(*List).Push",
+			Size:    30,
+			Instances: []*entity.Instance{
+				{TypeArgs: "go.shape.int", Shape: true, Addr: 0x1000, Size: 20},
+				{TypeArgs: "string", Addr: 0x1100, Size: 10},
+			},
+		}},
+		Inlines: []*entity.Inline{
+			{Package: "main", Function: "strings.Index", Origin: "strings", Calls: 2, Size: 40},
+		},
+		Imports: []why.Edge{{From: "main", To: "strings"}, {From: "main", To: "unicode", Uses: true}},
+		Why: []*why.Result{
+			{Target: "strings", Chains: []why.Chain{{{Package: "main", Size: 60}, {Package: "strings", Size: 40}}}},
+			{Target: "unreachable"},
+		},
+	}
+
+	assertMarshalJavaScriptCross(t, r)
+}
+
+func TestResultMarshalJavaScriptPackageGraph(t *testing.T) {
+	strings := entity.NewPackage()
+	strings.Name = "strings"
+	strings.Type = entity.PackageTypeStd
+	strings.Size = 40
+	strings.ImportedBy = []string{"main"}
+	strings.UsedBy = []string{"main"}
+	strings.Retained = 40
+
+	main := entity.NewPackage()
+	main.Name = "main"
+	main.Type = entity.PackageTypeMain
+	main.Size = 60
+
+	r := &result.Result{
+		Name:     "bin",
+		Size:     100,
+		Packages: entity.PackageMap{"main": main, "strings": strings},
+	}
+
+	assertMarshalJavaScriptCross(t, r)
+}
+
+// assertMarshalJavaScriptCross checks the result passed to the web UI matches the json output.
+func assertMarshalJavaScriptCross(t *testing.T, r *result.Result) {
+	t.Helper()
+
 	jsonPrinterResult, err := json.Marshal(r,
 		json.DefaultOptionsV2(),
 		json.Deterministic(true),
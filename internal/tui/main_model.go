@@ -10,7 +10,9 @@ import (
 	"charm.land/bubbles/v2/table"
 	tea "charm.land/bubbletea/v2"
 
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
 	"github.com/Zxilly/go-size-analyzer/internal/result"
+	"github.com/Zxilly/go-size-analyzer/internal/why"
 )
 
 var _ tea.Model = (*mainModel)(nil)
@@ -27,6 +29,10 @@ type mainModel struct {
 
 	fileName string
 
+	imports  *why.Graph
+	retained bool              // whether the packages have retained sizes
+	whyCache map[string]string // shared by the copies of the model
+
 	leftTable   hoverTable
 	rightDetail detailModel
 	help        help.Model
@@ -120,10 +126,20 @@ func buildRootItems(r *result.Result) wrappers {
 	return ret
 }
 
-func newLeftTable(width int, rows []table.Row) hoverTable {
+// hasRetained reports whether any package has a retained size, which requires the imports.
+func hasRetained(pkgs entity.PackageMap) bool {
+	for _, p := range pkgs {
+		if p.Retained > 0 || hasRetained(p.SubPackages) {
+			return true
+		}
+	}
+	return false
+}
+
+func newLeftTable(width int, rows []table.Row, retained bool) hoverTable {
 	return hoverTable{
 		Model: table.New(
-			table.WithColumns(getTableColumnsForTableWidth(width)),
+			table.WithColumns(getTableColumnsForTableWidth(width, retained)),
 			table.WithRows(rows),
 			table.WithFocused(true),
 		),
@@ -133,12 +149,16 @@ func newLeftTable(width int, rows []table.Row) hoverTable {
 
 func newMainModel(r *result.Result, width, height int) mainModel {
 	baseItems := buildRootItems(r)
+	retained := hasRetained(r.Packages)
 
 	m := mainModel{
 		baseItems:   baseItems,
 		fileName:    r.Name,
+		imports:     why.NewGraph(r.Packages),
+		whyCache:    make(map[string]string),
+		retained:    retained,
 		rightDetail: newDetailModel(),
-		leftTable:   newLeftTable(width, baseItems.ToRows()),
+		leftTable:   newLeftTable(width, baseItems.ToRows(retained), retained),
 		help:        help.New(),
 		focus:       focusedMain,
 	}
@@ -19,7 +19,7 @@ func (m mainModel) reconcileLayout() mainModel {
 	leftTop := firstVisibleRow(m.leftTable.Model)
 	if next.leftTable.w != m.layout.leftTable.w {
 		m.leftTable.SetWidth(next.leftTable.w)
-		m.leftTable.SetColumns(getTableColumnsForTableWidth(next.leftTable.w))
+		m.leftTable.SetColumns(getTableColumnsForTableWidth(next.leftTable.w, m.retained))
 	}
 	if next.leftTable.h != m.layout.leftTable.h {
 		m.leftTable.SetHeight(next.leftTable.h)
@@ -39,7 +39,11 @@ func (m mainModel) reconcileLayout() mainModel {
 
 func (m mainModel) reconcileSelection() mainModel {
 	sel := m.currentSelection()
-	m.rightDetail.SetMarkdown(sel.Description())
+	md := sel.Description()
+	if sel.pkg != nil {
+		md += m.whyDescription(sel.pkg.Name)
+	}
+	m.rightDetail.SetMarkdown(md)
 	tableSetStyles(&m.leftTable, getTableStyle(sel.hasChildren()))
 	return m
 }
@@ -2,20 +2,38 @@ package tui
 
 import "charm.land/bubbles/v2/table"
 
-func getTableColumnsForTableWidth(tableWidth int) []table.Column {
+// getTableColumnsForTableWidth lays out the columns, the retained column is
+// only shown when the packages have retained sizes.
+func getTableColumnsForTableWidth(tableWidth int, retained bool) []table.Column {
+	count := 2
+	if retained {
+		count = 3
+	}
+
 	styles := table.DefaultStyles()
-	cellFrameWidth := styles.Cell.GetHorizontalFrameSize() * 2
+	cellFrameWidth := styles.Cell.GetHorizontalFrameSize() * count
 	contentWidth := max(tableWidth-cellFrameWidth, 0)
 	sizeWidth := min(rowWidthSize, contentWidth)
+	retainedWidth := 0
+	if retained {
+		retainedWidth = min(rowWidthSize, contentWidth-sizeWidth)
+	}
 
-	return []table.Column{
+	columns := []table.Column{
 		{
 			Title: "Name",
-			Width: max(contentWidth-sizeWidth, 0),
+			Width: max(contentWidth-sizeWidth-retainedWidth, 0),
 		},
 		{
 			Title: "Size",
 			Width: sizeWidth,
 		},
 	}
+	if retained {
+		columns = append(columns, table.Column{
+			Title: "Retained",
+			Width: retainedWidth,
+		})
+	}
+	return columns
 }
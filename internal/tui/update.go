@@ -40,7 +40,7 @@ func (m mainModel) enterSelection() mainModel {
 	}
 	m = m.pushParent()
 	m.current = m.currentSelection()
-	m.leftTable = newLeftTable(m.width, m.current.children().ToRows())
+	m.leftTable = newLeftTable(m.width, m.current.children().ToRows(m.retained), m.retained)
 	m.layout = tuiLayout{}
 	m = m.clearHover()
 	return m.reconcile()
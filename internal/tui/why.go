@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/Zxilly/go-size-analyzer/internal/why"
+)
+
+// whyMarkdown lists the shortest import chains from main to the package of r.
+func whyMarkdown(r *why.Result) string {
+	sb := new(strings.Builder)
+
+	writeln := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(sb, format+"\n", args...)
+	}
+
+	writeln("")
+	writeln("## Why")
+	writeln("")
+	if len(r.Chains) == 0 {
+		writeln("No import chain from %s found", why.Root)
+		return sb.String()
+	}
+	for i, chain := range r.Chains {
+		links := make([]string, len(chain))
+		for j, l := range chain {
+			links[j] = fmt.Sprintf("%s (%s)", markdownText(l.Package), humanize.Bytes(l.Size))
+		}
+		writeln("%d. %s", i+1, strings.Join(links, " → "))
+	}
+	return sb.String()
+}
+
+// whyDescription is the why panel of the package name, empty without import information.
+func (m mainModel) whyDescription(name string) string {
+	if !m.imports.HasImports() {
+		return ""
+	}
+	if md, ok := m.whyCache[name]; ok {
+		return md
+	}
+
+	md := ""
+	if r, err := m.imports.Query(name); err == nil {
+		md = whyMarkdown(r)
+	}
+	m.whyCache[name] = md
+	return md
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+	"github.com/Zxilly/go-size-analyzer/internal/result"
+)
+
+func TestWhyPanel(t *testing.T) {
+	mainPkg := entity.NewPackage()
+	mainPkg.Name = "main"
+	mainPkg.Type = entity.PackageTypeMain
+	mainPkg.Size = 10
+
+	dep := entity.NewPackage()
+	dep.Name = "example.com/dep"
+	dep.Type = entity.PackageTypeVendor
+	dep.Size = 2000
+	dep.ImportedBy = []string{"main"}
+
+	m := newMainModel(&result.Result{
+		Name:     "bin",
+		Packages: entity.PackageMap{"main": mainPkg, "example.com/dep": dep},
+	}, 120, 40)
+
+	// the larger package is selected first
+	assert.Contains(t, m.rightDetail.currentMD, "## Why")
+	assert.Contains(t, m.rightDetail.currentMD, "1. main (10 B) → example.com/dep (2.0 kB)")
+}
+
+func TestWhyPanelWithoutImports(t *testing.T) {
+	m := newMainModel(testResultWithPackages("pkg", 3), 120, 40)
+	assert.NotContains(t, m.rightDetail.currentMD, "## Why")
+}
+
+func TestRetainedColumn(t *testing.T) {
+	mainPkg := entity.NewPackage()
+	mainPkg.Name = "main"
+	mainPkg.Size = 10
+	mainPkg.Retained = 2010
+
+	dep := entity.NewPackage()
+	dep.Name = "example.com/dep"
+	dep.Size = 2000
+	dep.Retained = 2000
+	dep.ImportedBy = []string{"main"}
+
+	m := newMainModel(&result.Result{
+		Name:     "bin",
+		Packages: entity.PackageMap{"main": mainPkg, "example.com/dep": dep},
+	}, 120, 40)
+
+	cols := m.leftTable.Columns()
+	assert.Len(t, cols, 3)
+	assert.Equal(t, "Retained", cols[2].Title)
+	assert.Equal(t, []string{"example.com/dep", "2.0 kB", "2.0 kB"}, []string(m.leftTable.Rows()[0]))
+	assert.Contains(t, m.rightDetail.currentMD, "- **Retained Size:** 2.0 kB (2000 Bytes)")
+}
+
+func TestRetainedColumnWithoutImports(t *testing.T) {
+	m := newMainModel(testResultWithPackages("pkg", 3), 120, 40)
+	assert.Len(t, m.leftTable.Columns(), 2)
+}
@@ -24,9 +24,9 @@ const (
 
 type wrappers []wrapper
 
-func (w wrappers) ToRows() []table.Row {
+func (w wrappers) ToRows(retained bool) []table.Row {
 	return lo.Map(w, func(item wrapper, _ int) table.Row {
-		return item.toRow()
+		return item.toRow(retained)
 	})
 }
 
@@ -182,6 +182,9 @@ func (w *wrapper) describe() string {
 		writeln("# %s _(Package)_", markdownText(w.pkg.Name))
 		writeln("")
 		writeln(sizeLine("Size", w.pkg.Size))
+		if w.pkg.Retained > 0 {
+			writeln(sizeLine("Retained Size", w.pkg.Retained))
+		}
 		writeln("- **Type:** %s", markdownText(w.pkg.Type))
 
 		if len(w.pkg.ImportedBy) > 0 {
@@ -192,6 +195,14 @@ func (w *wrapper) describe() string {
 				writeln("- %s", markdownText(k))
 			}
 		}
+		if len(w.pkg.UsedBy) > 0 {
+			writeln("")
+			writeln("## Used By")
+			writeln("")
+			for _, k := range w.pkg.UsedBy {
+				writeln("- %s", markdownText(k))
+			}
+		}
 		if len(w.pkg.Files) > 0 {
 			writeln("")
 			writeln("## Files")
@@ -240,16 +251,19 @@ func (w *wrapper) describe() string {
 			writeln("")
 			funcs := slices.Clone(w.file.Functions)
 			slices.SortFunc(funcs, func(a, b *entity.Function) int {
-				return -cmp.Compare(a.Size(), b.Size())
+				return -cmp.Compare(a.FullSize(), b.FullSize())
 			})
 			for _, k := range funcs {
-				writeln("- `%s` — %s", functionDisplayName(k), humanize.Bytes(k.Size()))
+				writeln("- `%s` — %s", functionDisplayName(k), humanize.Bytes(k.FullSize()))
 			}
 		}
 	case w.function != nil:
 		writeln("# %s _(Function)_", markdownText(functionDisplayName(w.function)))
 		writeln("")
-		writeln(sizeLine("Size", w.function.Size()))
+		writeln(sizeLine("Size", w.function.FullSize()))
+		if len(w.function.Wrappers) > 0 {
+			writeln(sizeLine("Own Size", w.function.Size()))
+		}
 		writeln("- **Type:** %s", markdownText(w.function.Type))
 		if w.function.Type == entity.FuncTypeMethod {
 			writeln("- **Receiver:** %s", markdownText(w.function.Receiver))
@@ -276,6 +290,15 @@ func (w *wrapper) describe() string {
 				writeln("- **%s:** %d Bytes", k, w.function.PclnSize.PCData[k])
 			}
 		}
+
+		if len(w.function.Wrappers) > 0 {
+			writeln("")
+			writeln("## Wrappers")
+			writeln("")
+			for _, k := range w.function.Wrappers {
+				writeln("- `%s` — %s", functionDisplayName(k), humanize.Bytes(k.FullSize()))
+			}
+		}
 	default:
 		panic("unreachable")
 	}
@@ -318,17 +341,25 @@ func (w *wrapper) size() uint64 {
 	case w.file != nil:
 		return w.file.FullSize()
 	case w.function != nil:
-		return w.function.Size()
+		return w.function.FullSize()
 	default:
 		panic("invalid wrapper")
 	}
 }
 
-func (w *wrapper) toRow() table.Row {
-	return table.Row{
+func (w *wrapper) toRow(retained bool) table.Row {
+	row := table.Row{
 		w.Title(),
 		humanize.Bytes(w.size()),
 	}
+	if retained {
+		cell := ""
+		if w.pkg != nil && w.pkg.Retained > 0 {
+			cell = humanize.Bytes(w.pkg.Retained)
+		}
+		row = append(row, cell)
+	}
+	return row
 }
 
 func (w *wrapper) hasChildren() bool {
@@ -341,8 +372,13 @@ func (w *wrapper) children() wrappers {
 		switch {
 		case w.pkg != nil:
 			ret = buildPackageChildren(w.pkg)
-		case w.section != nil || w.function != nil:
+		case w.section != nil:
 			ret = make([]wrapper, 0)
+		case w.function != nil:
+			ret = lo.Map(w.function.Wrappers, func(item *entity.Function, _ int) wrapper {
+				return newWrapper(item)
+			})
+			sortWrappers(ret)
 		case w.file != nil:
 			ret = lo.Map(w.file.Functions, func(item *entity.Function, _ int) wrapper {
 				return newWrapper(item)
@@ -133,3 +133,21 @@ func Test_wrapper_size(t *testing.T) {
 		w.size()
 	})
 }
+
+func Test_wrapper_FunctionWrappers(t *testing.T) {
+	closure := &entity.Function{Name: "Run.func1", Type: entity.FuncTypeFunction, CodeSize: 20}
+	w := newWrapper(&entity.Function{
+		Name:     "Run",
+		Type:     entity.FuncTypeFunction,
+		CodeSize: 100,
+		Wrappers: []*entity.Function{closure},
+	})
+
+	assert.Equal(t, uint64(120), w.size())
+	assert.True(t, w.hasChildren())
+	assert.Equal(t, closure, w.children()[0].function)
+
+	desc := w.Description()
+	assert.Contains(t, desc, "- **Own Size:** 100 B")
+	assert.Contains(t, desc, "## Wrappers\n\n- `Run.func1` — 20 B")
+}
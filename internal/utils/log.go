@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -33,6 +34,11 @@ func UsePanicForExit() {
 	}
 }
 
+// ExitCoder is implemented by errors that request a specific exit status.
+type ExitCoder interface {
+	ExitCode() int
+}
+
 func FatalError(err error) {
 	if err == nil {
 		return
@@ -40,7 +46,13 @@ func FatalError(err error) {
 
 	slog.Error(fmt.Sprintf("Fatal error: %v", err))
 
-	exitFunc(1)
+	code := 1
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		code = coder.ExitCode()
+	}
+
+	exitFunc(code)
 }
 
 type SyncOutput struct {
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,6 +24,22 @@ func TestFatalError(t *testing.T) {
 	assert.True(t, m.AssertCalled(t, "os.Exit", 1))
 }
 
+type exitCodeError struct{}
+
+func (exitCodeError) Error() string { return "exit code error" }
+func (exitCodeError) ExitCode() int { return 3 }
+
+func TestFatalErrorWithExitCoder(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("os.Exit", 3).Return()
+	exitFunc = func(code int) {
+		m.MethodCalled("os.Exit", code)
+	}
+
+	FatalError(fmt.Errorf("wrapped: %w", exitCodeError{}))
+	assert.True(t, m.AssertCalled(t, "os.Exit", 3))
+}
+
 func TestUsePanicForExit(t *testing.T) {
 	m := &mock.Mock{}
 	m.On("os.Exit", 1).Return()
@@ -0,0 +1,107 @@
+package why
+
+// postorder lists the packages reachable from Root, each one after the
+// packages first reached through it.
+func (g *Graph) postorder() []string {
+	ret := make([]string, 0, len(g.dist))
+	if _, ok := g.dist[Root]; !ok {
+		return ret
+	}
+
+	visited := map[string]bool{Root: true}
+	var visit func(name string)
+	visit = func(name string) {
+		for _, next := range g.imports[name] {
+			if !visited[next] {
+				visited[next] = true
+				visit(next)
+			}
+		}
+		ret = append(ret, name)
+	}
+	visit(Root)
+
+	return ret
+}
+
+// dominators returns the immediate dominator of each package reachable
+// from Root but Root itself, along with the postorder of the packages.
+// It is the iterative algorithm of Cooper, Harvey and Kennedy.
+func (g *Graph) dominators() (map[string]string, []string) {
+	order := g.postorder()
+	index := make(map[string]int, len(order))
+	for i, name := range order {
+		index[name] = i
+	}
+
+	// walk up the dominator tree from a and b to their common dominator
+	intersect := func(idom map[string]string, a, b string) string {
+		for a != b {
+			for index[a] < index[b] {
+				a = idom[a]
+			}
+			for index[b] < index[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+
+	idom := map[string]string{Root: Root}
+	for changed := true; changed; {
+		changed = false
+		// reverse postorder, skipping Root which is the last one
+		for i := len(order) - 2; i >= 0; i-- {
+			name := order[i]
+
+			dom := ""
+			for _, importer := range g.importers(g.packages[name]) {
+				if _, ok := idom[importer]; !ok {
+					continue
+				}
+				if dom == "" {
+					dom = importer
+				} else {
+					dom = intersect(idom, importer, dom)
+				}
+			}
+
+			if idom[name] != dom {
+				idom[name] = dom
+				changed = true
+			}
+		}
+	}
+
+	delete(idom, Root)
+	return idom, order
+}
+
+// Retained returns the retained size of each package reachable from Root,
+// the own size of the packages only reachable through it, itself included.
+// It is what dropping the package would save.
+func (g *Graph) Retained() map[string]uint64 {
+	idom, order := g.dominators()
+
+	ret := make(map[string]uint64, len(order))
+	// a package comes after all the packages it dominates in postorder
+	for _, name := range order {
+		ret[name] += g.packages[name].OwnSize()
+		if dom, ok := idom[name]; ok {
+			ret[dom] += ret[name]
+		}
+	}
+	return ret
+}
+
+// SetRetained fills Package.Retained of the packages of g, the packages
+// not reachable from Root are left zero.
+func (g *Graph) SetRetained() {
+	if !g.HasImports() {
+		return
+	}
+
+	for name, size := range g.Retained() {
+		g.packages[name].Retained = size
+	}
+}
@@ -0,0 +1,85 @@
+package why
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+func newSizedPackage(name string, size uint64, importedBy ...string) *entity.Package {
+	p := entity.NewPackage()
+	p.Name = name
+	p.Size = size
+	p.ImportedBy = importedBy
+	return p
+}
+
+func TestRetained(t *testing.T) {
+	pkgs := entity.PackageMap{
+		"main": newSizedPackage("main", 1),
+		"a":    newSizedPackage("a", 2, "main"),
+		"b":    newSizedPackage("b", 4, "main"),
+		"c":    newSizedPackage("c", 8, "a", "b"),
+		"d":    newSizedPackage("d", 16, "c", "e"),
+		"e":    newSizedPackage("e", 32, "d"),
+		"f":    newSizedPackage("f", 64, "a"),
+		"g":    newSizedPackage("g", 128, "f"),
+		"h":    newSizedPackage("h", 256, "unknown"),
+	}
+
+	assert.Equal(t, map[string]uint64{
+		"main": 255,
+		"a":    194,
+		"b":    4,
+		"c":    56,
+		"d":    48,
+		"e":    32,
+		"f":    192,
+		"g":    128,
+	}, NewGraph(pkgs).Retained())
+}
+
+func TestRetainedSubPackages(t *testing.T) {
+	x := newSizedPackage("x", 30, "main")
+	x.SubPackages["y"] = newSizedPackage("x/y", 10, "x")
+	pkgs := entity.PackageMap{
+		"main": newSizedPackage("main", 5),
+		"x":    x,
+	}
+
+	assert.Equal(t, map[string]uint64{
+		"main": 35,
+		"x":    30,
+		"x/y":  10,
+	}, NewGraph(pkgs).Retained())
+}
+
+func TestSetRetained(t *testing.T) {
+	pkgs := entity.PackageMap{
+		"main": newSizedPackage("main", 1),
+		"a":    newSizedPackage("a", 2, "main"),
+		"b":    newSizedPackage("b", 4, "a"),
+		"c":    newSizedPackage("c", 8),
+	}
+
+	NewGraph(pkgs).SetRetained()
+
+	assert.Equal(t, uint64(7), pkgs["main"].Retained)
+	assert.Equal(t, uint64(6), pkgs["a"].Retained)
+	assert.Equal(t, uint64(4), pkgs["b"].Retained)
+	assert.Zero(t, pkgs["c"].Retained)
+}
+
+func TestSetRetainedWithoutImports(t *testing.T) {
+	pkgs := entity.PackageMap{
+		"main": newSizedPackage("main", 1),
+		"a":    newSizedPackage("a", 2),
+	}
+
+	NewGraph(pkgs).SetRetained()
+
+	assert.Zero(t, pkgs["main"].Retained)
+	assert.Zero(t, pkgs["a"].Retained)
+}
@@ -0,0 +1,207 @@
+// Package why explains why a package is in the binary, with the shortest
+// import chains from the main package to it, and what dropping it would save.
+package why
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// Root is the package the import chains start from.
+const Root = "main"
+
+// MaxChains limits the chains of a target, there can be exponentially many shortest ones.
+const MaxChains = 10
+
+var ErrPackageNotFound = errors.New("package not found")
+
+// Link is a package of an import chain.
+type Link struct {
+	Package string `json:"package"`
+	Size    uint64 `json:"size"`
+}
+
+// Chain is an import chain from Root to a target, each package imports the next one.
+type Chain []Link
+
+// Result holds the shortest import chains to Target, it has no chain if
+// Target is not reachable with the known imports.
+type Result struct {
+	Target string  `json:"target"`
+	Chains []Chain `json:"chains"`
+}
+
+// Edge is an import of a package by another one.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Uses marks an edge of a uses graph, From calls To directly.
+	Uses bool `json:"uses,omitzero"`
+}
+
+// Graph is the import graph of the packages, built from Package.ImportedBy,
+// or from Package.UsedBy if no import is known, which only needs the call graph.
+type Graph struct {
+	packages map[string]*entity.Package
+	imports  map[string][]string
+	// uses is whether the graph is built from Package.UsedBy
+	uses bool
+	// dist is the length of the shortest import chain from Root
+	dist map[string]int
+}
+
+func NewGraph(pkgs entity.PackageMap) *Graph {
+	g := &Graph{
+		packages: make(map[string]*entity.Package),
+		imports:  make(map[string][]string),
+		dist:     make(map[string]int),
+	}
+
+	var walk func(pkgs entity.PackageMap)
+	walk = func(pkgs entity.PackageMap) {
+		for _, p := range pkgs {
+			g.packages[p.Name] = p
+			walk(p.SubPackages)
+		}
+	}
+	walk(pkgs)
+
+	// fall back to the calls only without any import
+	g.uses = true
+	for _, p := range g.packages {
+		if len(p.ImportedBy) > 0 {
+			g.uses = false
+			break
+		}
+	}
+	for _, p := range g.packages {
+		for _, importer := range g.importers(p) {
+			g.imports[importer] = append(g.imports[importer], p.Name)
+		}
+	}
+
+	if _, ok := g.packages[Root]; !ok {
+		return g
+	}
+
+	g.dist[Root] = 0
+	queue := []string{Root}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range g.imports[cur] {
+			if _, ok := g.dist[next]; ok {
+				continue
+			}
+			g.dist[next] = g.dist[cur] + 1
+			queue = append(queue, next)
+		}
+	}
+
+	return g
+}
+
+// importers returns the packages importing p, or using it for a uses graph.
+func (g *Graph) importers(p *entity.Package) []string {
+	if g.uses {
+		return p.UsedBy
+	}
+	return p.ImportedBy
+}
+
+// HasImports reports whether any import is known, which requires the imports
+// analysis or the call graph.
+func (g *Graph) HasImports() bool {
+	return len(g.dist) > 1
+}
+
+// Uses reports whether the graph is built from the calls instead of the imports.
+func (g *Graph) Uses() bool {
+	return g.uses
+}
+
+// Edges returns the imports, or the uses of a uses graph, sorted by importer
+// then imported package, nil without imports.
+func (g *Graph) Edges() []Edge {
+	var ret []Edge
+	for from, tos := range g.imports {
+		for _, to := range tos {
+			ret = append(ret, Edge{From: from, To: to, Uses: g.uses})
+		}
+	}
+	slices.SortFunc(ret, func(a, b Edge) int {
+		return cmp.Or(strings.Compare(a.From, b.From), strings.Compare(a.To, b.To))
+	})
+	return ret
+}
+
+// Query returns up to MaxChains shortest import chains to target, sorted by package names.
+func (g *Graph) Query(target string) (*Result, error) {
+	if _, ok := g.packages[target]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, target)
+	}
+
+	ret := &Result{Target: target, Chains: make([]Chain, 0)}
+
+	if _, ok := g.dist[target]; !ok {
+		return ret, nil
+	}
+
+	// walk back from target, each step to an importer one step closer to Root
+	var back func(name string, suffix []Link)
+	back = func(name string, suffix []Link) {
+		if len(ret.Chains) >= MaxChains {
+			return
+		}
+
+		p := g.packages[name]
+		suffix = append(suffix, Link{Package: name, Size: p.Size})
+
+		if name == Root {
+			chain := slices.Clone(suffix)
+			slices.Reverse(chain)
+			ret.Chains = append(ret.Chains, chain)
+			return
+		}
+
+		for _, importer := range g.importers(p) {
+			if d, ok := g.dist[importer]; ok && d == g.dist[name]-1 {
+				back(importer, suffix)
+			}
+		}
+	}
+	back(target, nil)
+
+	slices.SortFunc(ret.Chains, func(a, b Chain) int {
+		return slices.CompareFunc(a, b, func(x, y Link) int {
+			return strings.Compare(x.Package, y.Package)
+		})
+	})
+
+	return ret, nil
+}
+
+// Query builds the graph of pkgs and queries each target.
+func Query(pkgs entity.PackageMap, targets []string) ([]*Result, error) {
+	g := NewGraph(pkgs)
+	if !g.HasImports() {
+		slog.Warn("No import found from " + Root + ", the imports analysis requires the package sources, " +
+			"the call graph works without them")
+	}
+
+	ret := make([]*Result, 0, len(targets))
+	for _, target := range targets {
+		r, err := g.Query(target)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
@@ -0,0 +1,32 @@
+//go:build js && wasm
+
+package why
+
+import (
+	"github.com/samber/lo"
+)
+
+func (e Edge) MarshalJavaScript() any {
+	ret := map[string]any{
+		"from": e.From,
+		"to":   e.To,
+	}
+	if e.Uses {
+		ret["uses"] = true
+	}
+	return ret
+}
+
+func (r *Result) MarshalJavaScript() any {
+	return map[string]any{
+		"target": r.Target,
+		"chains": lo.Map(r.Chains, func(c Chain, _ int) any {
+			return lo.Map(c, func(l Link, _ int) any {
+				return map[string]any{
+					"package": l.Package,
+					"size":    l.Size,
+				}
+			})
+		}),
+	}
+}
@@ -0,0 +1,189 @@
+package why
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Zxilly/go-size-analyzer/internal/entity"
+)
+
+// newPackages builds top level packages from the importers of each package.
+func newPackages(importedBy map[string][]string) entity.PackageMap {
+	pkgs := make(entity.PackageMap)
+	size := uint64(10)
+	for name, importers := range importedBy {
+		p := entity.NewPackage()
+		p.Name = name
+		p.Size = size
+		p.ImportedBy = importers
+		pkgs[name] = p
+		size += 10
+	}
+	return pkgs
+}
+
+func chainNames(c Chain) []string {
+	ret := make([]string, len(c))
+	for i, l := range c {
+		ret[i] = l.Package
+	}
+	return ret
+}
+
+func TestQueryShortestChains(t *testing.T) {
+	pkgs := newPackages(map[string][]string{
+		"main": nil,
+		"a":    {"main"},
+		"b":    {"main"},
+		"c":    {"a", "b"},
+		"d":    {"c", "e"},
+		"e":    {"main"},
+		"f":    {"d"},
+	})
+
+	r, err := NewGraph(pkgs).Query("d")
+	require.NoError(t, err)
+
+	assert.Equal(t, "d", r.Target)
+	require.Len(t, r.Chains, 1)
+	assert.Equal(t, []string{"main", "e", "d"}, chainNames(r.Chains[0]))
+	assert.Equal(t, pkgs["e"].Size, r.Chains[0][1].Size)
+
+	r, err = NewGraph(pkgs).Query("c")
+	require.NoError(t, err)
+	require.Len(t, r.Chains, 2)
+	assert.Equal(t, []string{"main", "a", "c"}, chainNames(r.Chains[0]))
+	assert.Equal(t, []string{"main", "b", "c"}, chainNames(r.Chains[1]))
+
+	r, err = NewGraph(pkgs).Query("main")
+	require.NoError(t, err)
+	assert.Equal(t, []Chain{{{Package: "main", Size: pkgs["main"].Size}}}, r.Chains)
+}
+
+func TestQuerySubPackages(t *testing.T) {
+	sub := entity.NewPackage()
+	sub.Name = "example.com/a/b"
+	sub.Size = 5
+	sub.ImportedBy = []string{"main"}
+
+	a := entity.NewPackage()
+	a.Name = "example.com/a"
+	a.Size = 15
+	a.ImportedBy = []string{"example.com/a/b"}
+	a.SubPackages["b"] = sub
+
+	m := entity.NewPackage()
+	m.Name = "main"
+
+	r, err := NewGraph(entity.PackageMap{"main": m, "example.com/a": a}).Query("example.com/a")
+	require.NoError(t, err)
+	assert.Equal(t, []Chain{{
+		{Package: "main"},
+		{Package: "example.com/a/b", Size: 5},
+		{Package: "example.com/a", Size: 15},
+	}}, r.Chains)
+}
+
+func TestQueryUnreachable(t *testing.T) {
+	g := NewGraph(newPackages(map[string][]string{
+		"main": nil,
+		"a":    {"main"},
+		"b":    nil,
+	}))
+	assert.True(t, g.HasImports())
+
+	r, err := g.Query("b")
+	require.NoError(t, err)
+	assert.Empty(t, r.Chains)
+
+	_, err = g.Query("missing")
+	require.ErrorIs(t, err, ErrPackageNotFound)
+}
+
+func TestQueryWithoutImports(t *testing.T) {
+	g := NewGraph(newPackages(map[string][]string{
+		"main": nil,
+		"a":    nil,
+	}))
+	assert.False(t, g.HasImports())
+
+	r, err := g.Query("a")
+	require.NoError(t, err)
+	assert.Empty(t, r.Chains)
+}
+
+func TestQueryLimitsChains(t *testing.T) {
+	importedBy := map[string][]string{"main": nil, "target": nil}
+	for _, mid := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		importedBy[mid] = []string{"main"}
+		importedBy["target"] = append(importedBy["target"], mid)
+	}
+
+	r, err := NewGraph(newPackages(importedBy)).Query("target")
+	require.NoError(t, err)
+	require.Len(t, r.Chains, MaxChains)
+	assert.Equal(t, []string{"main", "a", "target"}, chainNames(r.Chains[0]))
+}
+
+func TestQueryTargets(t *testing.T) {
+	pkgs := newPackages(map[string][]string{
+		"main": nil,
+		"a":    {"main"},
+	})
+
+	rs, err := Query(pkgs, []string{"a", "main"})
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, "a", rs[0].Target)
+	assert.Equal(t, "main", rs[1].Target)
+
+	_, err = Query(pkgs, []string{"missing"})
+	require.ErrorIs(t, err, ErrPackageNotFound)
+}
+
+func TestEdges(t *testing.T) {
+	pkgs := newPackages(map[string][]string{
+		"main": nil,
+		"b":    {"main", "a"},
+		"a":    {"main"},
+		"c":    {"b"},
+	})
+
+	assert.Equal(t, []Edge{
+		{From: "a", To: "b"},
+		{From: "b", To: "c"},
+		{From: "main", To: "a"},
+		{From: "main", To: "b"},
+	}, NewGraph(pkgs).Edges())
+
+	assert.Nil(t, NewGraph(newPackages(map[string][]string{"main": nil})).Edges())
+}
+
+func TestUsesGraph(t *testing.T) {
+	pkgs := entity.PackageMap{
+		"main": newSizedPackage("main", 1),
+		"a":    newSizedPackage("a", 2),
+		"b":    newSizedPackage("b", 4),
+	}
+	pkgs["a"].UsedBy = []string{"main"}
+	pkgs["b"].UsedBy = []string{"a"}
+
+	g := NewGraph(pkgs)
+	assert.True(t, g.Uses())
+	assert.True(t, g.HasImports())
+
+	r, err := g.Query("b")
+	require.NoError(t, err)
+	require.Len(t, r.Chains, 1)
+	assert.Equal(t, []string{"main", "a", "b"}, chainNames(r.Chains[0]))
+	assert.Equal(t, uint64(6), g.Retained()["a"])
+	assert.Equal(t, []Edge{{From: "a", To: "b", Uses: true}, {From: "main", To: "a", Uses: true}}, g.Edges())
+
+	// the imports are preferred when known
+	pkgs["b"].ImportedBy = []string{"main"}
+	g = NewGraph(pkgs)
+	assert.False(t, g.Uses())
+	assert.Equal(t, []Edge{{From: "main", To: "b"}}, g.Edges())
+}
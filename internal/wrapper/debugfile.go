@@ -0,0 +1,298 @@
+package wrapper
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/blacktop/go-macho"
+)
+
+// DefaultDebugDirs are the directories searched for debug files after the
+// ones given by the user.
+var DefaultDebugDirs = []string{"/usr/lib/debug"}
+
+// ntGNUBuildID is the type of the ELF note holding the GNU build id.
+const ntGNUBuildID = 3
+
+var ErrDebugFileMismatch = errors.New("debug file does not match the binary")
+
+// DebugFileLoader is implemented by the wrappers whose DWARF may be kept in a
+// companion debug file.
+type DebugFileLoader interface {
+	// FindDebugFile returns the debug file of the binary at path, looked up
+	// next to it and in dirs, or "" if none is found.
+	FindDebugFile(path string, dirs []string) string
+	// LoadDebugFile makes DWARF return the DWARF of the debug file at path,
+	// it fails if the debug file does not match the binary.
+	LoadDebugFile(path string) error
+}
+
+var (
+	_ DebugFileLoader = (*ElfWrapper)(nil)
+	_ DebugFileLoader = (*MachoWrapper)(nil)
+)
+
+// isFile reports whether path exists and is not a directory.
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+// align4 rounds n up to a multiple of 4, the alignment of the ELF notes.
+func align4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// elfBuildID returns the GNU build id of f in hex, or "" if it has none.
+func elfBuildID(f *elf.File) string {
+	s := f.Section(".note.gnu.build-id")
+	if s == nil {
+		return ""
+	}
+	b, err := s.Data()
+	if err != nil {
+		return ""
+	}
+
+	order := f.ByteOrder
+	for len(b) >= 12 {
+		nameSize, descSize, typ := int(order.Uint32(b)), int(order.Uint32(b[4:])), order.Uint32(b[8:])
+		b = b[12:]
+		if len(b) < align4(nameSize)+descSize {
+			return ""
+		}
+		name, desc := b[:nameSize], b[align4(nameSize):align4(nameSize)+descSize]
+		if typ == ntGNUBuildID && string(name) == "GNU\x00" {
+			return hex.EncodeToString(desc)
+		}
+		b = b[min(len(b), align4(nameSize)+align4(descSize)):]
+	}
+	return ""
+}
+
+// elfDebugLink returns the file name and checksum in the .gnu_debuglink section of f.
+func elfDebugLink(f *elf.File) (name string, crc uint32, ok bool) {
+	s := f.Section(".gnu_debuglink")
+	if s == nil {
+		return "", 0, false
+	}
+	b, err := s.Data()
+	if err != nil {
+		return "", 0, false
+	}
+
+	end := bytes.IndexByte(b, 0)
+	if end <= 0 || len(b) < align4(end+1)+4 {
+		return "", 0, false
+	}
+	return string(b[:end]), f.ByteOrder.Uint32(b[align4(end+1):]), true
+}
+
+// fileCRC32 returns the checksum of the file at path as .gnu_debuglink records it.
+func fileCRC32(path string) (uint32, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	// debug files are often hundreds of MB, they are streamed
+	h := crc32.NewIEEE()
+	if _, err = io.Copy(h, f); err != nil {
+		return 0, false
+	}
+	return h.Sum32(), true
+}
+
+// FindDebugFile looks the debug file up by the build id under dirs, as
+// dir/.build-id/ab/cdef.debug, then by the .gnu_debuglink next to the binary,
+// in its .debug directory and under dirs.
+func (e *ElfWrapper) FindDebugFile(path string, dirs []string) string {
+	if id := elfBuildID(e.file); len(id) > 2 {
+		for _, dir := range dirs {
+			p := filepath.Join(dir, ".build-id", id[:2], id[2:]+".debug")
+			if isFile(p) {
+				return p
+			}
+		}
+	}
+
+	name, crc, ok := elfDebugLink(e.file)
+	if !ok {
+		return ""
+	}
+	binDir := filepath.Dir(path)
+	candidates := []string{filepath.Join(binDir, name), filepath.Join(binDir, ".debug", name)}
+	if abs, err := filepath.Abs(binDir); err == nil {
+		for _, dir := range dirs {
+			candidates = append(candidates, filepath.Join(dir, abs, name))
+		}
+	}
+	for _, dir := range dirs {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+
+	for _, p := range candidates {
+		if filepath.Clean(p) == filepath.Clean(path) || !isFile(p) {
+			continue
+		}
+		if sum, ok := fileCRC32(p); ok && sum == crc {
+			return p
+		}
+	}
+	return ""
+}
+
+// elfDebugMatches checks the debug file dbg is built from the binary bin,
+// by the build id and the address of the code.
+func elfDebugMatches(bin, dbg *elf.File) error {
+	if bin.Machine != dbg.Machine || bin.Class != dbg.Class {
+		return fmt.Errorf("%w: machine %s, want %s", ErrDebugFileMismatch, dbg.Machine, bin.Machine)
+	}
+	if id, did := elfBuildID(bin), elfBuildID(dbg); id != "" && did != "" && id != did {
+		return fmt.Errorf("%w: build id %s, want %s", ErrDebugFileMismatch, did, id)
+	}
+
+	text, dtext := bin.Section(".text"), dbg.Section(".text")
+	if text == nil {
+		return nil
+	}
+	if dtext == nil || dtext.Addr != text.Addr || dtext.Size != text.Size {
+		return fmt.Errorf("%w: .text section differs", ErrDebugFileMismatch)
+	}
+	return nil
+}
+
+func (e *ElfWrapper) LoadDebugFile(path string) error {
+	f, err := elf.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err = elfDebugMatches(e.file, f); err != nil {
+		return err
+	}
+	d, err := f.DWARF()
+	if err != nil {
+		return err
+	}
+	e.debug = d
+	return nil
+}
+
+// dsymFile returns the DWARF file of the .dSYM bundle at path, or path itself
+// if it is not a directory.
+func dsymFile(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return path, nil
+	}
+
+	dir := filepath.Join(path, "Contents", "Resources", "DWARF")
+	named := filepath.Join(dir, strings.TrimSuffix(filepath.Base(path), ".dSYM"))
+	if isFile(named) {
+		return named, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(files) != 1 {
+		return "", fmt.Errorf("%s holds %d DWARF files, want one", path, len(files))
+	}
+	return files[0], nil
+}
+
+// FindDebugFile looks the name.dSYM bundle up next to the binary and in dirs.
+func (m *MachoWrapper) FindDebugFile(path string, dirs []string) string {
+	name := filepath.Base(path) + ".dSYM"
+	candidates := []string{path + ".dSYM"}
+	for _, dir := range dirs {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+
+	for _, p := range candidates {
+		if _, err := dsymFile(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// machoDebugMatches checks the debug file dbg is built from the binary bin,
+// by the uuid and the address of the code.
+func machoDebugMatches(bin, dbg *macho.File) error {
+	if bin.CPU != dbg.CPU {
+		return fmt.Errorf("%w: cpu %s, want %s", ErrDebugFileMismatch, dbg.CPU, bin.CPU)
+	}
+	if id, did := bin.UUID(), dbg.UUID(); id != nil && did != nil && id.UUID != did.UUID {
+		return fmt.Errorf("%w: uuid %s, want %s", ErrDebugFileMismatch, did.UUID, id.UUID)
+	}
+
+	text, dtext := bin.Section("__TEXT", "__text"), dbg.Section("__TEXT", "__text")
+	if text == nil {
+		return nil
+	}
+	if dtext == nil || dtext.Addr != text.Addr || dtext.Size != text.Size {
+		return fmt.Errorf("%w: __text section differs", ErrDebugFileMismatch)
+	}
+	return nil
+}
+
+// LoadDebugFile loads a DWARF file, or the one in a .dSYM bundle. The slice
+// of the architecture of the binary is picked from a universal DWARF file.
+func (m *MachoWrapper) LoadDebugFile(path string) error {
+	path, err := dsymFile(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.ReaderAt = file
+	if fat, ok := FatSlices(file); ok {
+		arch := m.GoArch()
+		i := slices.IndexFunc(fat, func(s FatSlice) bool { return s.Arch == arch })
+		if i < 0 {
+			return fmt.Errorf("%w: no %s slice", ErrDebugFileMismatch, arch)
+		}
+		r = io.NewSectionReader(file, int64(fat[i].Offset), int64(fat[i].Size))
+	}
+	f, err := macho.NewFile(r)
+	if err != nil {
+		return err
+	}
+
+	if err = machoDebugMatches(m.file, f); err != nil {
+		return err
+	}
+	d, err := NewMachoWrapper(f).DWARF()
+	if err != nil {
+		return err
+	}
+	m.debug = d
+	return nil
+}
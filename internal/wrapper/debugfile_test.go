@@ -0,0 +1,95 @@
+package wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blacktop/go-macho"
+	"github.com/blacktop/go-macho/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDsym creates a .dSYM bundle holding the DWARF files names.
+func writeDsym(t *testing.T, bundle string, names ...string) {
+	t.Helper()
+	dir := filepath.Join(bundle, "Contents", "Resources", "DWARF")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+}
+
+func TestDsymFile(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDsym(t, filepath.Join(dir, "app.dSYM"), "app", "other")
+	p, err := dsymFile(filepath.Join(dir, "app.dSYM"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "app.dSYM", "Contents", "Resources", "DWARF", "app"), p)
+
+	// a renamed bundle holds a single file
+	writeDsym(t, filepath.Join(dir, "renamed.dSYM"), "app")
+	p, err = dsymFile(filepath.Join(dir, "renamed.dSYM"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "renamed.dSYM", "Contents", "Resources", "DWARF", "app"), p)
+
+	writeDsym(t, filepath.Join(dir, "many.dSYM"), "a", "b")
+	_, err = dsymFile(filepath.Join(dir, "many.dSYM"))
+	assert.Error(t, err)
+
+	// a plain file is the DWARF file itself
+	plain := filepath.Join(dir, "app.dwarf")
+	require.NoError(t, os.WriteFile(plain, nil, 0o644))
+	p, err = dsymFile(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, p)
+
+	_, err = dsymFile(filepath.Join(dir, "missing.dSYM"))
+	assert.Error(t, err)
+}
+
+func TestMachoFindDebugFile(t *testing.T) {
+	bin := t.TempDir()
+	debug := t.TempDir()
+	m := &MachoWrapper{}
+
+	assert.Empty(t, m.FindDebugFile(filepath.Join(bin, "app"), []string{debug}))
+
+	writeDsym(t, filepath.Join(debug, "app.dSYM"), "app")
+	assert.Equal(t, filepath.Join(debug, "app.dSYM"), m.FindDebugFile(filepath.Join(bin, "app"), []string{debug}))
+
+	// next to the binary comes first
+	writeDsym(t, filepath.Join(bin, "app.dSYM"), "app")
+	assert.Equal(t, filepath.Join(bin, "app.dSYM"), m.FindDebugFile(filepath.Join(bin, "app"), []string{debug}))
+}
+
+func TestMachoDebugMatches(t *testing.T) {
+	file := func(cpu types.CPU) *macho.File {
+		return &macho.File{FileTOC: macho.FileTOC{FileHeader: types.FileHeader{CPU: cpu}}}
+	}
+
+	require.NoError(t, machoDebugMatches(file(types.CPUArm64), file(types.CPUArm64)))
+	assert.ErrorIs(t, machoDebugMatches(file(types.CPUArm64), file(types.CPUAmd64)), ErrDebugFileMismatch)
+}
+
+func TestAlign4(t *testing.T) {
+	assert.Equal(t, 0, align4(0))
+	assert.Equal(t, 4, align4(1))
+	assert.Equal(t, 4, align4(4))
+	assert.Equal(t, 8, align4(5))
+}
+
+func TestFileCRC32(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.debug")
+	require.NoError(t, os.WriteFile(name, []byte("123456789"), 0o644))
+
+	// the check value of CRC-32/IEEE
+	sum, ok := fileCRC32(name)
+	require.True(t, ok)
+	assert.Equal(t, uint32(0xcbf43926), sum)
+
+	_, ok = fileCRC32(filepath.Join(t.TempDir(), "missing.debug"))
+	assert.False(t, ok)
+}
@@ -25,6 +25,7 @@ type ElfWrapper struct {
 	file      *elf.File
 	relocs    []relocEntry // sorted by offset; built lazily
 	relocOnce sync.Once
+	debug     *dwarf.Data // from the debug file, see LoadDebugFile
 }
 
 func (e *ElfWrapper) buildRelocs() {
@@ -82,6 +83,9 @@ func (e *ElfWrapper) buildRelocs() {
 var _ RawFileWrapper = (*ElfWrapper)(nil)
 
 func (e *ElfWrapper) DWARF() (*dwarf.Data, error) {
+	if e.debug != nil {
+		return e.debug, nil
+	}
 	return e.file.DWARF()
 }
 
@@ -307,6 +311,13 @@ func (e *ElfWrapper) GoArch() string {
 		return "ppc64"
 	case elf.EM_S390:
 		return "s390x"
+	case elf.EM_RISCV:
+		if e.file.Class == elf.ELFCLASS64 {
+			return "riscv64"
+		}
+		return ""
+	case elf.EM_LOONGARCH:
+		return "loong64"
 	default:
 		return ""
 	}
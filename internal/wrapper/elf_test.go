@@ -21,12 +21,15 @@ func TestGoArchReturnsExpectedArchitectures(t *testing.T) {
 		{elf.EM_PPC64, "ppc64", binary.BigEndian},      // Adjusted for big endian
 		{elf.EM_PPC64, "ppc64le", binary.LittleEndian}, // Explicitly little endian
 		{elf.EM_S390, "s390x", binary.BigEndian},
+		{elf.EM_RISCV, "riscv64", binary.LittleEndian},
+		{elf.EM_LOONGARCH, "loong64", binary.LittleEndian},
 		{0, "", binary.LittleEndian}, // Test for an unsupported machine type
 	}
 
 	for _, test := range tests {
 		mockFile := new(elf.File)
 		mockFile.FileHeader = elf.FileHeader{
+			Class:     elf.ELFCLASS64,
 			Machine:   test.machine,
 			ByteOrder: test.byteOrder,
 		}
@@ -0,0 +1,70 @@
+package wrapper
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/blacktop/go-macho/types"
+)
+
+const (
+	fatMagic   = 0xcafebabe
+	fatMagic64 = 0xcafebabf
+
+	// fatMaxArch bounds the count of slices like LLVM and file(1) do, the magic
+	// is shared by the Java class files, whose major version from 45 on is read
+	// as the count.
+	fatMaxArch = 42
+)
+
+// FatSlice is the Mach-O file of an architecture in a universal binary.
+type FatSlice struct {
+	// Arch is the Go architecture, or the cpu type if Go does not support it.
+	Arch   string
+	Offset uint64
+	Size   uint64
+}
+
+// FatSlices returns the slices of the universal Mach-O binary r, in the order
+// of the header. ok is false if r is not a universal binary.
+func FatSlices(r io.ReaderAt) (slices []FatSlice, ok bool) {
+	var header [8]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, false
+	}
+
+	magic := binary.BigEndian.Uint32(header[:])
+	if magic != fatMagic && magic != fatMagic64 {
+		return nil, false
+	}
+	count := binary.BigEndian.Uint32(header[4:])
+	if count == 0 || count > fatMaxArch {
+		return nil, false
+	}
+
+	entrySize := 20
+	if magic == fatMagic64 {
+		entrySize = 32
+	}
+	entries := make([]byte, int(count)*entrySize)
+	if _, err := r.ReadAt(entries, int64(len(header))); err != nil {
+		return nil, false
+	}
+
+	slices = make([]FatSlice, count)
+	for i := range slices {
+		e := entries[i*entrySize:]
+		cpu := types.CPU(binary.BigEndian.Uint32(e))
+		s := FatSlice{Arch: machoGoArch(cpu)}
+		if s.Arch == "" {
+			s.Arch = cpu.String()
+		}
+		if magic == fatMagic64 {
+			s.Offset, s.Size = binary.BigEndian.Uint64(e[8:]), binary.BigEndian.Uint64(e[16:])
+		} else {
+			s.Offset, s.Size = uint64(binary.BigEndian.Uint32(e[8:])), uint64(binary.BigEndian.Uint32(e[12:]))
+		}
+		slices[i] = s
+	}
+	return slices, true
+}
@@ -0,0 +1,67 @@
+package wrapper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/blacktop/go-macho/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fatHeader builds the header of a universal binary holding the slices.
+func fatHeader(magic uint32, slices ...FatSlice) []byte {
+	cpus := map[string]types.CPU{"amd64": types.CPUAmd64, "arm64": types.CPUArm64, "sparc": types.CPUSparc}
+
+	b := binary.BigEndian.AppendUint32(nil, magic)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(slices)))
+	for _, s := range slices {
+		b = binary.BigEndian.AppendUint32(b, uint32(cpus[s.Arch]))
+		b = binary.BigEndian.AppendUint32(b, 0) // cpu subtype
+		if magic == fatMagic64 {
+			b = binary.BigEndian.AppendUint64(b, s.Offset)
+			b = binary.BigEndian.AppendUint64(b, s.Size)
+			b = binary.BigEndian.AppendUint32(b, 14) // align
+			b = binary.BigEndian.AppendUint32(b, 0)  // reserved
+		} else {
+			b = binary.BigEndian.AppendUint32(b, uint32(s.Offset))
+			b = binary.BigEndian.AppendUint32(b, uint32(s.Size))
+			b = binary.BigEndian.AppendUint32(b, 14) // align
+		}
+	}
+	return b
+}
+
+func TestFatSlices(t *testing.T) {
+	want := []FatSlice{{Arch: "amd64", Offset: 0x4000, Size: 0x1000}, {Arch: "arm64", Offset: 0x8000, Size: 0x2000}}
+
+	for _, magic := range []uint32{fatMagic, fatMagic64} {
+		slices, ok := FatSlices(bytes.NewReader(fatHeader(magic, want...)))
+		require.True(t, ok)
+		assert.Equal(t, want, slices)
+	}
+
+	// architectures Go does not support keep the cpu name
+	slices, ok := FatSlices(bytes.NewReader(fatHeader(fatMagic, FatSlice{Arch: "sparc", Offset: 0x4000, Size: 0x10})))
+	require.True(t, ok)
+	assert.Equal(t, types.CPUSparc.String(), slices[0].Arch)
+}
+
+func TestFatSlicesNotFat(t *testing.T) {
+	_, ok := FatSlices(bytes.NewReader([]byte("\x7fELF\x02\x01\x01\x00")))
+	assert.False(t, ok)
+
+	_, ok = FatSlices(bytes.NewReader(nil))
+	assert.False(t, ok)
+
+	// a Java class file shares the magic, its version is read as the count
+	for _, major := range []byte{45, 52, 0x41, 66} {
+		_, ok = FatSlices(bytes.NewReader([]byte{0xca, 0xfe, 0xba, 0xbe, 0x00, 0x00, 0x00, major}))
+		assert.False(t, ok, "major version %d", major)
+	}
+
+	// truncated entries
+	_, ok = FatSlices(bytes.NewReader(fatHeader(fatMagic, FatSlice{Arch: "amd64"})[:16]))
+	assert.False(t, ok)
+}
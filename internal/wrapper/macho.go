@@ -21,6 +21,7 @@ type MachoWrapper struct {
 	file           *macho.File
 	chainedFixedUp bool
 	memOnly        []addrRange // sorted, merged memory-only ranges (zerofill/bss)
+	debug          *dwarf.Data // from the debug file, see LoadDebugFile
 }
 
 var _ RawFileWrapper = (*MachoWrapper)(nil)
@@ -123,6 +124,10 @@ func (m *MachoWrapper) SlidePointer(addr uint64) uint64 {
 
 // DWARF a copy of go-macho's DWARF function
 func (m *MachoWrapper) DWARF() (*dwarf.Data, error) {
+	if m.debug != nil {
+		return m.debug, nil
+	}
+
 	dwarfSuffix := func(s *types.Section) string {
 		switch {
 		case strings.HasPrefix(s.Name, "__debug_"):
@@ -395,7 +400,12 @@ func (m *MachoWrapper) Text() (textStart uint64, text []byte, err error) {
 }
 
 func (m *MachoWrapper) GoArch() string {
-	switch m.file.CPU {
+	return machoGoArch(m.file.CPU)
+}
+
+// machoGoArch returns the Go architecture of cpu, or "" if Go does not support it.
+func machoGoArch(cpu types.CPU) string {
+	switch cpu {
 	case types.CPUI386:
 		return "386"
 	case types.CPUAmd64:
@@ -85,6 +85,7 @@ func (w *WasmWrapper) LoadSections() *entity.Store {
 	return store
 }
 
+// DWARF always fails, the Go linker does not emit DWARF for js and wasip1.
 func (*WasmWrapper) DWARF() (*dwarf.Data, error) {
 	return nil, errors.New("dwarf section not supported")
 }
@@ -170,3 +170,9 @@ func TestWasmComputeDataSectUsedMergesOverlappingSymbols(t *testing.T) {
 	got := w.ComputeDataSectUsed(symbols)
 	assert.Equal(t, uint64(0x50), got)
 }
+
+func TestWasmDWARFNotSupported(t *testing.T) {
+	// the Go linker emits no .debug_* custom sections for wasm
+	_, err := (&WasmWrapper{module: &wasm.Module{}}).DWARF()
+	assert.Error(t, err)
+}
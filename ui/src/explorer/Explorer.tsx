@@ -1,5 +1,6 @@
 import type { ReactNode } from "react";
-import { Box, Dialog, DialogContent, DialogContentText, DialogTitle } from "@mui/material";
+import type { ProgressEvent } from "../worker/event.ts";
+import { Box, Dialog, DialogContent, DialogContentText, DialogTitle, LinearProgress } from "@mui/material";
 import * as React from "react";
 import { useMemo } from "react";
 import { useAsync } from "react-use";
@@ -32,6 +33,20 @@ const LogViewer: React.FC<{ log: string }> = ({ log }) => {
   );
 };
 
+const ProgressViewer: React.FC<{ progress: ProgressEvent }> = ({ progress }) => {
+  const { phase, done, total } = progress;
+  return (
+    <Box marginBottom={1}>
+      <DialogContentText>
+        {total > 0 ? `${phase} ${done}/${total}` : phase}
+      </DialogContentText>
+      {total > 0
+        ? <LinearProgress variant="determinate" value={done * 100 / total} />
+        : <LinearProgress />}
+    </Box>
+  );
+};
+
 export const Explorer: React.FC = () => {
   const [log, setLog] = React.useState<string>("");
 
@@ -43,6 +58,7 @@ export const Explorer: React.FC = () => {
   });
 
   const [file, setFile] = React.useState<File | null>(null);
+  const [progress, setProgress] = React.useState<ProgressEvent | null>(null);
 
   const { value: result, loading: analyzing } = useAsync(async () => {
     if (!file || !analyzer) {
@@ -52,7 +68,8 @@ export const Explorer: React.FC = () => {
     const bytes = await file.arrayBuffer();
     const uint8 = new Uint8Array(bytes);
 
-    return analyzer.analyze(file.name, uint8);
+    setProgress(null);
+    return analyzer.analyze(file.name, uint8, setProgress);
   }, [file]);
 
   const entry = useMemo(() => {
@@ -122,7 +139,10 @@ export const Explorer: React.FC = () => {
       isOpen: true,
       title: `Analyzing ${file.name}`,
       content: (
-        <LogViewer log={log} />
+        <>
+          {progress && <ProgressViewer progress={progress} />}
+          <LogViewer log={log} />
+        </>
       ),
     };
   }
@@ -41,6 +41,8 @@ interface PackageRef {
   symbols: FileSymbol[];
   size: number;
   importedBy?: string[];
+  usedBy?: string[];
+  retained?: number;
 }
 
 export const PackageSchema: GenericSchema<PackageRef> = object({
@@ -58,16 +60,78 @@ export const PackageSchema: GenericSchema<PackageRef> = object({
   symbols: array(FileSymbolSchema),
   size: number(),
   importedBy: optional(array(string())),
+  usedBy: optional(array(string())),
+  retained: optional(number()),
 });
 
 export type Package = InferInput<typeof PackageSchema>;
 
+export const ModuleSchema = object({
+  path: string(),
+  version: string(),
+  replace: optional(string()),
+  type: union([literal("main"), literal("std"), literal("vendor"), literal("unknown")]),
+  packages: array(string()),
+  size: number(),
+});
+
+export type Module = InferInput<typeof ModuleSchema>;
+
+export const InstanceSchema = object({
+  type_args: string(),
+  shape: boolean(),
+  addr: number(),
+  size: number(),
+});
+
+export const GenericFunctionSchema = object({
+  package: string(),
+  name: string(),
+  size: number(),
+  instances: array(InstanceSchema),
+});
+
+export type GenericFunction = InferInput<typeof GenericFunctionSchema>;
+
+export const InlineSchema = object({
+  package: string(),
+  function: string(),
+  origin: string(),
+  calls: number(),
+  size: number(),
+});
+
+export type Inline = InferInput<typeof InlineSchema>;
+
+export const ImportEdgeSchema = object({
+  from: string(),
+  to: string(),
+  uses: optional(boolean()),
+});
+
+export type ImportEdge = InferInput<typeof ImportEdgeSchema>;
+
+export const WhySchema = object({
+  target: string(),
+  chains: array(array(object({
+    package: string(),
+    size: number(),
+  }))),
+});
+
+export type Why = InferInput<typeof WhySchema>;
+
 export const ResultSchema = object({
   name: string(),
   size: number(),
   packages: record(string(), PackageSchema),
   sections: array(SectionSchema),
   analyzers: optional(array(union([literal("dwarf"), literal("disasm"), literal("symbol"), literal("pclntab"), literal("type"), literal("pclntab_meta")]))),
+  modules: optional(array(ModuleSchema)),
+  generics: optional(array(GenericFunctionSchema)),
+  inlines: optional(array(InlineSchema)),
+  imports: optional(array(ImportEdgeSchema)),
+  why: optional(array(WhySchema)),
 });
 
 export type Result = InferInput<typeof ResultSchema>;
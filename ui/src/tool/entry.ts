@@ -165,6 +165,9 @@ export class PackageImpl extends BaseImpl implements EntryLike<"package"> {
     align.add("Package:", this.data.name)
       .add("Type:", this.data.type)
       .add("Size:", formatBytes(this.data.size));
+    if (this.data.retained) {
+      align.add("Retained:", formatBytes(this.data.retained));
+    }
     let content = align.toString();
     if (this.data.importedBy && this.data.importedBy.length > 0) {
       content += `\n\n`
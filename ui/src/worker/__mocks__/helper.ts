@@ -1,5 +1,6 @@
 import type { Result } from "../../schema/schema.ts";
 import { getTestResult } from "../../test/testhelper.ts";
+import type { ProgressEvent } from "../event.ts";
 
 export class GsaInstance {
   log: any;
@@ -12,7 +13,7 @@ export class GsaInstance {
     return new GsaInstance({}, {});
   }
 
-  async analyze(filename: string, _data: Uint8Array): Promise<Result | null> {
+  async analyze(filename: string, _data: Uint8Array, _onProgress?: (progress: ProgressEvent) => void): Promise<Result | null> {
     if (filename === "fail") {
       return null;
     }
@@ -14,4 +14,11 @@ export interface LogEvent {
   line: string;
 }
 
-export type WasmEvent = LoadEvent | AnalyzeEvent | LogEvent;
+export interface ProgressEvent {
+  type: "progress";
+  phase: string;
+  done: number;
+  total: number;
+}
+
+export type WasmEvent = LoadEvent | AnalyzeEvent | LogEvent | ProgressEvent;
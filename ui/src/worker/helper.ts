@@ -1,5 +1,5 @@
 import type { Result } from "../schema/schema.ts";
-import type { LoadEvent, WasmEvent } from "./event.ts";
+import type { LoadEvent, ProgressEvent, WasmEvent } from "./event.ts";
 import worker from "./worker.ts?worker&url";
 
 export class GsaInstance {
@@ -40,7 +40,11 @@ export class GsaInstance {
     });
   }
 
-  async analyze(filename: string, data: Uint8Array): Promise<Result | null> {
+  async analyze(
+    filename: string,
+    data: Uint8Array,
+    onProgress?: (progress: ProgressEvent) => void,
+  ): Promise<Result | null> {
     return new Promise((resolve) => {
       const analyzeCb = (e: MessageEvent<WasmEvent>) => {
         const data = e.data;
@@ -49,6 +53,9 @@ export class GsaInstance {
           case "log":
             this.logHandler(data.line);
             break;
+          case "progress":
+            onProgress?.(data);
+            break;
           case "analyze":
             this.worker.removeEventListener("message", analyzeCb);
             resolve(data.result);
@@ -1,10 +1,14 @@
-import type { AnalyzeEvent, LoadEvent, LogEvent } from "./event.ts";
+import type { AnalyzeEvent, LoadEvent, LogEvent, ProgressEvent } from "./event.ts";
 import gsa from "../../gsa.wasm?init";
 import { setCallback } from "../runtime/fs";
 import "../runtime/wasm_exec.js";
 
 declare const self: DedicatedWorkerGlobalScope;
-declare function gsa_analyze(name: string, data: Uint8Array): import("../schema/schema.ts").Result;
+declare function gsa_analyze(
+  name: string,
+  data: Uint8Array,
+  onProgress?: (phase: string, done: number, total: number) => void,
+): import("../schema/schema.ts").Result;
 
 async function init() {
   const go = new Go();
@@ -39,7 +43,14 @@ init().then(() => {
 self.onmessage = (e: MessageEvent<[string, Uint8Array]>) => {
   const [filename, data] = e.data;
 
-  const result = gsa_analyze(filename, data);
+  const result = gsa_analyze(filename, data, (phase, done, total) => {
+    self.postMessage({
+      type: "progress",
+      phase,
+      done,
+      total,
+    } satisfies ProgressEvent);
+  });
 
   self.postMessage({
     result,